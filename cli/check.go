@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+
+	"github.com/holydocs/dberd"
+)
+
+// driftCode is the status code returned by --check when the existing output is out of date,
+// so CI jobs can tell schema drift from operational errors.
+const driftCode = 3
+
+// errSchemaDrift is returned by --check when the existing output differs from the generated one.
+var errSchemaDrift = errors.New("schema drift detected")
+
+var (
+	// timestamp matches RFC 3339 UTC timestamps, as used by the metadata extraction time.
+	timestamp = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z`)
+	// dberdVersion matches the dberd version of metadata titles, e.g. "by dberd v1.2.0".
+	dberdVersion = regexp.MustCompile(`by dberd \S+`)
+)
+
+// checkDrift compares the formatted schema with the existing output file and writes a diff to w
+// if they differ. Output files are formatted from the sorted schema without table stats, see output,
+// so they don't depend on extraction order and estimates. JSON schemas are also compared regardless
+// of order and metadata, other formats as text ignoring the metadata extraction time and dberd version.
+func checkDrift(path string, formatted []byte, w io.Writer) error {
+	existing, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(w, "%s does not exist\n", path)
+		return fmt.Errorf("%s: %w", path, errSchemaDrift)
+	}
+
+	if err != nil {
+		return fmt.Errorf("reading existing output: %w", err)
+	}
+
+	oldText, newText := normalizeOutput(existing), normalizeOutput(formatted)
+	if oldText == newText {
+		return nil
+	}
+
+	writeDiff(w, path, "generated", oldText, newText)
+
+	return fmt.Errorf("%s: %w", path, errSchemaDrift)
+}
+
+// normalizeOutput returns the output in a form suitable for comparison.
+func normalizeOutput(data []byte) string {
+	var schema dberd.Schema
+
+	if json.Valid(data) && json.Unmarshal(data, &schema) == nil {
+		canonicalize(&schema)
+
+		normalized, err := json.MarshalIndent(schema, "", "  ")
+		if err == nil {
+			return string(normalized) + "\n"
+		}
+	}
+
+	text := timestamp.ReplaceAllString(string(data), "<timestamp>")
+
+	return dberdVersion.ReplaceAllString(text, "by dberd <version>")
+}
@@ -0,0 +1,416 @@
+// Package cli implements the dberd command-line tool on top of the source and target registry.
+//
+// The tool runs the whole extract, format and render flow with the run command, which is the default,
+// while the extract, format and render commands run a single step, reading from stdin and writing
+// to stdout, so they can be combined in Unix pipelines.
+//
+// The CLI knows only about sources and targets registered in the dberd package, so a custom
+// binary can support additional implementations by importing their packages:
+//
+//	package main
+//
+//	import (
+//		"github.com/holydocs/dberd/cli"
+//		_ "github.com/holydocs/dberd/source/postgres"
+//		_ "github.com/holydocs/dberd/target/d2"
+//		_ "example.com/internal/dberdsource"
+//	)
+//
+//	func main() {
+//		cli.Main()
+//	}
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/holydocs/dberd"
+)
+
+// stdio is the name of the input and output files meaning stdin and stdout.
+const stdio = "-"
+
+// Main runs the CLI with the process arguments and exits with its status code.
+// Interrupting the process cancels the command context, which stops long-running commands.
+func Main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := Run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+
+	stop()
+	os.Exit(code)
+}
+
+// streams holds the standard streams of a command.
+type streams struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// command is a CLI subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string, s streams) int
+}
+
+func commands() []command {
+	return []command{
+		{name: "run", summary: "Extract, format and render the schema (default)", run: runCommand},
+		{name: "extract", summary: "Extract the schema as JSON", run: extractCommand},
+		{name: "format", summary: "Format a JSON schema with a target", run: formatCommand},
+		{name: "render", summary: "Render a formatted schema with a target", run: renderCommand},
+		{name: "watch", summary: "Regenerate the outputs whenever the schema changes", run: watchCommand},
+		{name: "serve", summary: "Serve the schemas over HTTP", run: serveCommand},
+		{name: "lint", summary: "Check the schema against design rules", run: lintCommand},
+	}
+}
+
+// Run runs the CLI with the given arguments and returns the process status code.
+// Arguments without a command run the run command.
+func Run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	s := streams{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runCommand(ctx, args, s)
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, args[1:], s)
+		}
+	}
+
+	fmt.Fprintf(stderr, "Error: unknown command %q\n\n", args[0])
+	printCommands(stderr)
+
+	return 1
+}
+
+// fail reports the error and returns the status code of failed commands.
+func fail(stderr io.Writer, err error) int {
+	fmt.Fprintf(stderr, "Error: %v\n", err)
+	return 1
+}
+
+func newFlagSet(name string, s streams) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(s.stderr)
+
+	return flags
+}
+
+// sourceFlags holds source command-line settings.
+type sourceFlags struct {
+	sourceType *string
+	dsn        *string
+	opts       dberd.Options
+}
+
+func addSourceFlags(flags *flag.FlagSet) *sourceFlags {
+	f := &sourceFlags{opts: dberd.Options{}}
+
+	f.sourceType = flags.String("source", "", "Source database type ("+sourceNames()+"), detected from the DSN scheme if omitted")
+	f.dsn = flags.String("source-dsn", "", "Connection string for source database")
+	flags.Var(optionsFlag(f.opts), "source-opt", "Source option as name=value, can be repeated")
+
+	return f
+}
+
+// isSet reports whether the source can be created, either by type or from the DSN scheme.
+func (f *sourceFlags) isSet() bool {
+	return *f.sourceType != "" || *f.dsn != ""
+}
+
+// targetFlags holds target command-line settings.
+type targetFlags struct {
+	targetType *string
+	opts       dberd.Options
+}
+
+func addTargetFlags(flags *flag.FlagSet) *targetFlags {
+	f := &targetFlags{opts: dberd.Options{}}
+
+	f.targetType = flags.String("target", "", "Target type ("+targetNames()+")")
+	flags.Var(optionsFlag(f.opts), "target-opt", "Target option as name=value or target.name=value to set it for one target only, can be repeated")
+
+	return f
+}
+
+// transforms holds schema transformation settings, set by command-line flags or in the config file.
+type transforms struct {
+	IncludeTables     string `yaml:"include_tables"`
+	ExcludeTables     string `yaml:"exclude_tables"`
+	DropColumns       string `yaml:"drop_columns"`
+	StripNamespace    string `yaml:"strip_namespace"`
+	HideUnreferenced  bool   `yaml:"hide_unreferenced"`
+	CollapseJunctions bool   `yaml:"collapse_junctions"`
+	InvalidReferences string `yaml:"invalid_references"`
+}
+
+func addTransformFlags(flags *flag.FlagSet) *transforms {
+	t := &transforms{}
+
+	flags.StringVar(&t.IncludeTables, "include-tables", "", "Keep only tables whose qualified name matches the regular expression")
+	flags.StringVar(&t.ExcludeTables, "exclude-tables", "", "Drop tables whose qualified name matches the regular expression")
+	flags.StringVar(&t.DropColumns, "drop-columns", "", "Drop columns whose name or table-qualified name matches the regular expression")
+	flags.StringVar(&t.StripNamespace, "strip-namespace", "", "Strip the comma-separated namespaces from table names, * strips any namespace")
+	flags.BoolVar(&t.HideUnreferenced, "hide-unreferenced", false, "Hide tables without references")
+	flags.BoolVar(&t.CollapseJunctions, "collapse-junctions", false, "Replace junction tables with many-to-many references")
+	flags.StringVar(&t.InvalidReferences, "invalid-references", "fail", "How to handle references to missing tables or columns (fail, drop)")
+
+	return t
+}
+
+// pipeline builds the schema transformation pipeline from the settings.
+func (t transforms) pipeline() (dberd.Pipeline, error) {
+	var p dberd.Pipeline
+
+	switch t.InvalidReferences {
+	case "", "fail", "drop":
+	default:
+		return nil, fmt.Errorf("unknown invalid references mode %q", t.InvalidReferences)
+	}
+
+	if t.IncludeTables != "" || t.ExcludeTables != "" {
+		filter, err := dberd.FilterTables(t.IncludeTables, t.ExcludeTables)
+		if err != nil {
+			return nil, err
+		}
+
+		p = append(p, filter)
+	}
+
+	if t.DropColumns != "" {
+		drop, err := dberd.DropColumns(t.DropColumns)
+		if err != nil {
+			return nil, err
+		}
+
+		p = append(p, drop)
+	}
+
+	if t.CollapseJunctions {
+		p = append(p, dberd.CollapseJunctionTables())
+	}
+
+	if t.HideUnreferenced {
+		p = append(p, dberd.HideUnreferencedTables())
+	}
+
+	switch t.StripNamespace {
+	case "":
+	case "*":
+		p = append(p, dberd.StripNamespace())
+	default:
+		p = append(p, dberd.StripNamespace(strings.Split(t.StripNamespace, ",")...))
+	}
+
+	if t.InvalidReferences == "drop" {
+		p = append(p, dberd.DropInvalidReferences())
+	}
+
+	return p, nil
+}
+
+// readInput reads the named file, or stdin if the name is "-".
+func readInput(name string, stdin io.Reader) ([]byte, error) {
+	if name == stdio {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading stdin: %w", err)
+		}
+
+		return data, nil
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	return data, nil
+}
+
+// writeOutput writes data to the named file, or stdout if the name is "-".
+func writeOutput(name string, data []byte, stdout io.Writer) error {
+	if name == stdio {
+		_, err := stdout.Write(data)
+		if err != nil {
+			return fmt.Errorf("writing to stdout: %w", err)
+		}
+
+		return nil
+	}
+
+	err := os.WriteFile(name, data, 0600)
+	if err != nil {
+		return fmt.Errorf("writing to file: %w", err)
+	}
+
+	return nil
+}
+
+// resolveSourceType returns the given source type, or detects it from the DSN scheme if it is empty.
+func resolveSourceType(ctx context.Context, sourceType, dsn string) (string, error) {
+	if sourceType != "" {
+		return sourceType, nil
+	}
+
+	r, err := dberd.ResolveSource(ctx, dsn)
+	if err != nil {
+		return "", fmt.Errorf("detecting source: %w", err)
+	}
+
+	return r.Name, nil
+}
+
+// extract creates the source and extracts the schema from it.
+func extract(ctx context.Context, sourceType, dsn string, opts dberd.Options) (dberd.Schema, error) {
+	source, err := dberd.NewSource(sourceType, dsn, opts)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("creating source: %w", err)
+	}
+
+	defer source.Close()
+
+	schema, err := source.ExtractSchema(ctx)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("extracting schema: %w", err)
+	}
+
+	return schema, nil
+}
+
+// optionsFlag is a flag.Value collecting repeated name=value options.
+type optionsFlag dberd.Options
+
+// String implements flag.Value.
+func (f optionsFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for name, value := range f {
+		pairs = append(pairs, name+"="+value)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value. A name without a value enables a boolean option.
+func (f optionsFlag) Set(s string) error {
+	name, value, found := strings.Cut(s, "=")
+	if name == "" {
+		return fmt.Errorf("invalid option %q, name=value expected", s)
+	}
+
+	if !found {
+		value = "true"
+	}
+
+	f[name] = value
+
+	return nil
+}
+
+// scopedOptions returns the options applying to the target: options without a target prefix
+// and options prefixed with the target name, e.g. "d2.size-scaling", without the prefix.
+func scopedOptions(opts dberd.Options, targetType string) dberd.Options {
+	scoped := make(dberd.Options, len(opts))
+
+	for name, value := range opts {
+		prefix, option, found := strings.Cut(name, ".")
+
+		switch {
+		case !found:
+			scoped[name] = value
+		case prefix == targetType:
+			scoped[option] = value
+		}
+	}
+
+	return scoped
+}
+
+func sourceNames() string {
+	var names []string
+	for _, r := range dberd.Sources() {
+		names = append(names, r.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func targetNames() string {
+	var names []string
+	for _, r := range dberd.Targets() {
+		names = append(names, r.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func printList(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Sources:\n")
+
+	for _, r := range dberd.Sources() {
+		fmt.Fprintf(tw, "  %s\t%s\n", r.Name, r.Description)
+		printOptions(tw, r.Options)
+	}
+
+	fmt.Fprintf(tw, "\nTargets:\n")
+
+	for _, r := range dberd.Targets() {
+		fmt.Fprintf(tw, "  %s\t%s\n", r.Name, r.Description)
+		printOptions(tw, r.Options)
+	}
+
+	tw.Flush()
+}
+
+func printOptions(w io.Writer, specs []dberd.OptionSpec) {
+	for _, spec := range specs {
+		fmt.Fprintf(w, "    %s\t%s", spec.Name, spec.Description)
+
+		if spec.Default != "" {
+			fmt.Fprintf(w, " (default %s)", spec.Default)
+		}
+
+		fmt.Fprintln(w)
+	}
+}
+
+func printCommands(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Commands:\n")
+
+	for _, cmd := range commands() {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.name, cmd.summary)
+	}
+
+	tw.Flush()
+}
+
+// printCommandUsage prints the command usage line, description, options and examples.
+func printCommandUsage(flags *flag.FlagSet, description string, examples ...string) {
+	w := flags.Output()
+
+	fmt.Fprintf(w, "Usage: %s [options]\n\n", flags.Name())
+	fmt.Fprintf(w, "%s\n\n", description)
+	fmt.Fprintf(w, "Options:\n")
+	flags.PrintDefaults()
+	fmt.Fprintf(w, "\nExample:\n")
+
+	for _, example := range examples {
+		fmt.Fprintf(w, "  %s\n", example)
+	}
+}
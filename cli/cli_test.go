@@ -0,0 +1,906 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/holydocs/dberd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSource struct {
+	stats    bool
+	dangling bool
+}
+
+func (s *testSource) ExtractSchema(_ context.Context) (dberd.Schema, error) {
+	schema := dberd.Schema{
+		Tables: []dberd.Table{
+			{Name: "public.users", Columns: []dberd.Column{{Name: "id", Definition: "INT8"}}},
+		},
+	}
+
+	if s.dangling {
+		schema.References = []dberd.Reference{
+			{
+				Source: dberd.TableColumn{Table: "public.users", Column: "id"},
+				Target: dberd.TableColumn{Table: "public.accounts", Column: "id"},
+			},
+		}
+	}
+
+	if s.stats {
+		schema.Tables[0].Stats = &dberd.TableStats{Rows: 1}
+	}
+
+	return schema, nil
+}
+
+func (s *testSource) Close() error {
+	return nil
+}
+
+// watchSchemas holds schemas extracted by the cli-watch test source by DSN.
+var watchSchemas sync.Map
+
+type watchSource struct {
+	dsn string
+}
+
+func (s *watchSource) ExtractSchema(_ context.Context) (dberd.Schema, error) {
+	schema, ok := watchSchemas.Load(s.dsn)
+	if !ok {
+		return dberd.Schema{}, errors.New("no schema")
+	}
+
+	return schema.(dberd.Schema), nil
+}
+
+func (s *watchSource) Close() error {
+	return nil
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+type testTarget struct{}
+
+func (t *testTarget) Capabilities() dberd.TargetCapabilities {
+	return dberd.TargetCapabilities{Format: true, Render: true}
+}
+
+func (t *testTarget) FormatSchema(_ context.Context, s dberd.Schema) (dberd.FormattedSchema, error) {
+	data := s.Tables[0].Name
+	if s.Tables[0].Stats != nil {
+		data += " " + s.Tables[0].Stats.Badge()
+	}
+
+	return dberd.FormattedSchema{Type: "cli-test", Data: []byte(data)}, nil
+}
+
+func (t *testTarget) RenderSchema(_ context.Context, s dberd.FormattedSchema) ([]byte, error) {
+	return []byte("<svg>" + string(s.Data) + "</svg>"), nil
+}
+
+func init() {
+	dberd.RegisterSource(dberd.SourceRegistration{
+		Name:        "cli-test",
+		Description: "CLI test source",
+		Schemes:     []string{"clitest"},
+		Options: []dberd.OptionSpec{
+			{Name: "stats", Description: "Extract stats", Default: "false"},
+			{Name: "dangling", Description: "Add a dangling reference", Default: "false"},
+		},
+		New: func(_ string, opts dberd.Options) (dberd.Source, error) {
+			stats, err := opts.Bool("stats")
+			if err != nil {
+				return nil, err
+			}
+
+			dangling, err := opts.Bool("dangling")
+			if err != nil {
+				return nil, err
+			}
+
+			return &testSource{stats: stats, dangling: dangling}, nil
+		},
+	})
+
+	dberd.RegisterSource(dberd.SourceRegistration{
+		Name:        "cli-watch",
+		Description: "CLI watch test source",
+		New: func(dsn string, _ dberd.Options) (dberd.Source, error) {
+			return &watchSource{dsn: dsn}, nil
+		},
+	})
+
+	dberd.RegisterTarget(dberd.TargetRegistration{
+		Name:          "cli-test",
+		Description:   "CLI test target",
+		SourceOptions: dberd.Options{"stats": "true"},
+		New: func(_ dberd.Options) (dberd.Target, error) {
+			return &testTarget{}, nil
+		},
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("lists registry", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{"--list"}, nil, &stdout, &stderr)
+		require.Equal(t, 0, code, stderr.String())
+		assert.Regexp(t, `(?m)^  cli-test +CLI test source\n    stats +Extract stats \(default false\)$`, stdout.String())
+		assert.Regexp(t, `(?m)^  cli-test +CLI test target$`, stdout.String())
+	})
+
+	t.Run("formats schema with target source options", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{"--source", "cli-test", "--target", "cli-test", "--format-to-file", "-"}, nil, &stdout, &stderr)
+		require.Equal(t, 0, code, stderr.String())
+		assert.Equal(t, "public.users ~1 rows", stdout.String())
+	})
+
+	t.Run("formats files without stats", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		out := filepath.Join(t.TempDir(), "schema.txt")
+
+		code := Run(ctx, []string{"--source", "cli-test", "--target", "cli-test", "--format-to-file", out}, nil, &stdout, &stderr)
+		require.Equal(t, 0, code, stderr.String())
+
+		data, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Equal(t, "public.users", string(data))
+	})
+
+	t.Run("detects source from dsn", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		out := filepath.Join(t.TempDir(), "schema.txt")
+
+		code := Run(ctx, []string{"--source-dsn", "clitest://localhost/app", "--target", "cli-test", "--format-to-file", out}, nil, &stdout, &stderr)
+		require.Equal(t, 0, code, stderr.String())
+
+		data, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Equal(t, "public.users", string(data))
+	})
+
+	t.Run("transforms schema", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		out := filepath.Join(t.TempDir(), "schema.txt")
+
+		code := Run(ctx, []string{
+			"--source", "cli-test", "--target", "cli-test", "--strip-namespace", "*",
+			"--format-to-file", out,
+		}, nil, &stdout, &stderr)
+		require.Equal(t, 0, code, stderr.String())
+
+		data, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Equal(t, "users", string(data))
+	})
+
+	t.Run("fails on invalid references", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{
+			"--source", "cli-test", "--source-opt", "dangling", "--target", "cli-test",
+			"--format-to-file", filepath.Join(t.TempDir(), "schema.txt"),
+		}, nil, &stdout, &stderr)
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "Error: validating schema: dangling reference: "+
+			"public.users.id -> public.accounts.id, unknown table public.accounts\n", stderr.String())
+	})
+
+	t.Run("drops invalid references", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{
+			"--source", "cli-test", "--source-opt", "dangling", "--target", "cli-test",
+			"--invalid-references", "drop", "--format-to-file", filepath.Join(t.TempDir(), "schema.txt"),
+		}, nil, &stdout, &stderr)
+		assert.Equal(t, 0, code, stderr.String())
+	})
+
+	t.Run("explicit source options win", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		out := filepath.Join(t.TempDir(), "schema.txt")
+
+		code := Run(ctx, []string{
+			"--source", "cli-test", "--source-opt", "stats=false",
+			"--target", "cli-test", "--format-to-file", out,
+		}, nil, &stdout, &stderr)
+		require.Equal(t, 0, code, stderr.String())
+
+		data, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Equal(t, "public.users", string(data))
+	})
+
+	t.Run("rejects unknown options", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{
+			"--source", "cli-test", "--target", "cli-test", "--target-opt", "color=red",
+			"--format-to-file", filepath.Join(t.TempDir(), "schema.txt"),
+		}, nil, &stdout, &stderr)
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "Error: creating target: target cli-test: unknown option \"color\"\n", stderr.String())
+	})
+}
+
+func TestRunCommands(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	var extracted, stderr bytes.Buffer
+
+	code := Run(ctx, []string{"extract", "--source", "cli-test", "--source-opt", "stats"}, nil, &extracted, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.JSONEq(t, `{
+		"tables": [{"name": "public.users", "columns": [{"name": "id", "definition": "INT8", "is_primary": false}], "stats": {"rows": 1}}],
+		"references": null
+	}`, extracted.String())
+
+	var formatted bytes.Buffer
+
+	code = Run(ctx, []string{"format", "--target", "cli-test"}, &extracted, &formatted, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, "public.users ~1 rows", formatted.String())
+
+	out := filepath.Join(t.TempDir(), "schema.svg")
+
+	code = Run(ctx, []string{"render", "--target", "cli-test", "--output", out}, &formatted, &bytes.Buffer{}, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "<svg>public.users ~1 rows</svg>", string(data))
+
+	t.Run("runs to stdout", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{"run", "--source", "cli-test", "--target", "cli-test", "--render-to-file", "-"}, nil, &stdout, &stderr)
+		require.Equal(t, 0, code, stderr.String())
+		assert.Equal(t, "<svg>public.users ~1 rows</svg>", stdout.String())
+	})
+
+	t.Run("rejects invalid schema", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{"format", "--target", "cli-test"}, strings.NewReader("<svg>"), &stdout, &stderr)
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "Error: decoding schema: invalid character '<' looking for beginning of value\n", stderr.String())
+	})
+
+	t.Run("rejects unknown commands", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{"draw"}, nil, &stdout, &stderr)
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr.String(), "Error: unknown command \"draw\"\n\nCommands:\n  run ")
+	})
+}
+
+func TestRunOutputs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+
+	code := Run(ctx, []string{
+		"--source", "cli-test",
+		"--output", "cli-test=" + filepath.Join(dir, "schema.txt") + "," + filepath.Join(dir, "schema.svg"),
+		"--output", "cli-test=-",
+	}, nil, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, "public.users ~1 rows", stdout.String())
+
+	data, err := os.ReadFile(filepath.Join(dir, "schema.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "public.users", string(data))
+
+	data, err = os.ReadFile(filepath.Join(dir, "schema.svg"))
+	require.NoError(t, err)
+	assert.Equal(t, "<svg>public.users ~1 rows</svg>", string(data))
+
+	t.Run("rejects several stdout outputs", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{"--source", "cli-test", "--output", "cli-test=-", "--output", "cli-test=,-"}, nil, &stdout, &stderr)
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "Error: only one output can be written to stdout\n", stderr.String())
+	})
+
+	t.Run("rejects mixed flags", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{"--source", "cli-test", "--target", "cli-test", "--output", "cli-test=-"}, nil, &stdout, &stderr)
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "Error: --output can't be combined with --target, --format-to-file and --render-to-file\n", stderr.String())
+	})
+}
+
+func TestScopedOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := dberd.Options{"routines": "true", "d2.size-scaling": "true", "mermaid.theme": "dark"}
+
+	assert.Equal(t, dberd.Options{"routines": "true", "size-scaling": "true"}, scopedOptions(opts, "d2"))
+	assert.Equal(t, dberd.Options{"routines": "true"}, scopedOptions(opts, "json"))
+}
+
+func TestRunCheck(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	out := filepath.Join(t.TempDir(), "schema.txt")
+	args := []string{"--source", "cli-test", "--target", "cli-test", "--format-to-file", out, "--check"}
+
+	var stdout, stderr bytes.Buffer
+
+	code := Run(ctx, args, nil, &stdout, &stderr)
+	assert.Equal(t, driftCode, code, stderr.String())
+	assert.Equal(t, out+" does not exist\n", stdout.String())
+
+	require.NoError(t, os.WriteFile(out, []byte("public.users"), 0o600))
+
+	stdout.Reset()
+	stderr.Reset()
+
+	code = Run(ctx, args, nil, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.Empty(t, stdout.String())
+
+	require.NoError(t, os.WriteFile(out, []byte("public.accounts"), 0o600))
+
+	stdout.Reset()
+	stderr.Reset()
+
+	code = Run(ctx, args, nil, &stdout, &stderr)
+	assert.Equal(t, driftCode, code)
+	assert.Equal(t, "--- "+out+"\n+++ generated\n@@ -1 +1 @@\n-public.accounts\n+public.users\n", stdout.String())
+	assert.Equal(t, out+": schema drift detected\n", stderr.String())
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "public.accounts", string(data), "--check must not overwrite the output")
+
+	t.Run("ignores order and stats", func(t *testing.T) {
+		t.Parallel()
+
+		dsn := t.Name()
+		out := filepath.Join(t.TempDir(), "schema.txt")
+		args := []string{"--source", "cli-watch", "--source-dsn", dsn, "--target", "cli-test", "--format-to-file", out}
+
+		users := dberd.Table{Name: "public.users", Columns: []dberd.Column{{Name: "id", Definition: "INT8"}}}
+		accounts := dberd.Table{Name: "public.accounts", Columns: []dberd.Column{{Name: "id", Definition: "INT8"}}}
+
+		users.Stats = &dberd.TableStats{Rows: 10}
+		accounts.Stats = &dberd.TableStats{Rows: 20}
+		watchSchemas.Store(dsn, dberd.Schema{Tables: []dberd.Table{users, accounts}})
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, args, nil, &stdout, &stderr)
+		require.Equal(t, 0, code, stderr.String())
+
+		users.Stats = &dberd.TableStats{Rows: 11}
+		accounts.Stats = &dberd.TableStats{Rows: 21}
+		watchSchemas.Store(dsn, dberd.Schema{Tables: []dberd.Table{accounts, users}})
+
+		code = Run(ctx, append(args, "--check"), nil, &stdout, &stderr)
+		assert.Equal(t, 0, code, stdout.String()+stderr.String())
+		assert.Empty(t, stdout.String())
+	})
+}
+
+func TestCheckDrift(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "schema.json")
+
+	existing := `{
+  "tables": [
+    {"name": "public.users", "columns": [{"name": "id", "definition": "INT8", "is_primary": true}]},
+    {"name": "public.posts", "columns": [{"name": "id", "definition": "INT8", "is_primary": true}]}
+  ],
+  "references": [],
+  "metadata": {"source": "postgres", "extracted_at": "2025-01-02T03:04:05Z", "version": "dev"}
+}`
+	require.NoError(t, os.WriteFile(path, []byte(existing), 0o600))
+
+	generated := `{
+  "tables": [
+    {"name": "public.posts", "columns": [{"name": "id", "definition": "INT8", "is_primary": true}]},
+    {"name": "public.users", "columns": [{"name": "id", "definition": "INT8", "is_primary": true}]}
+  ],
+  "references": [],
+  "metadata": {"source": "postgres", "extracted_at": "2026-01-02T03:04:05Z", "version": "dev"}
+}`
+
+	var diff bytes.Buffer
+
+	require.NoError(t, checkDrift(path, []byte(generated), &diff), "order and extraction time must be ignored")
+	assert.Empty(t, diff.String())
+
+	text := "title: postgres, extracted at 2025-01-02T03:04:05Z by dberd v1.0.0\na\nb\nc\nd\ne\nf\ng\n"
+	require.NoError(t, os.WriteFile(path, []byte(text), 0o600))
+
+	require.NoError(t, checkDrift(path, []byte("title: postgres, extracted at 2026-01-02T03:04:05Z by dberd v1.1.0\na\nb\nc\nd\ne\nf\ng\n"), &diff),
+		"extraction time and dberd version must be ignored")
+	assert.Empty(t, diff.String())
+
+	err := checkDrift(path, []byte("title: postgres, extracted at 2026-01-02T03:04:05Z by dberd dev\na\nb\nc\nD\ne\nf\ng\nh\n"), &diff)
+	require.ErrorIs(t, err, errSchemaDrift)
+	assert.Equal(t, "--- "+path+"\n+++ generated\n"+
+		"@@ -3 +3 @@\n b\n c\n-d\n+D\n e\n f\n g\n+h\n", diff.String())
+}
+
+func TestRunLint(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("fails on errors", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{"lint", "--source-dsn", "clitest://localhost/app"}, nil, &stdout, &stderr)
+		assert.Equal(t, lintFailureCode, code, stderr.String())
+		assert.Equal(t, "info: public.users: columns without comments: id (column-comment)\n"+
+			"error: public.users: table has no primary key (primary-key)\n"+
+			"2 findings: 1 errors, 0 warnings, 1 info\n", stdout.String())
+	})
+
+	t.Run("overrides severities", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{
+			"lint", "--source", "cli-test", "--severity", "primary-key=warning", "--severity", "column-comment=off",
+			"--format", "json",
+		}, nil, &stdout, &stderr)
+		require.Equal(t, 0, code, stderr.String())
+		assert.JSONEq(t, `[{"rule":"primary-key","severity":"warning","table":"public.users","message":"table has no primary key"}]`,
+			stdout.String())
+
+		stdout.Reset()
+
+		code = Run(ctx, []string{
+			"lint", "--source", "cli-test", "--severity", "primary-key=warning", "--fail-on", "warning",
+		}, nil, &stdout, &stderr)
+		assert.Equal(t, lintFailureCode, code, stderr.String())
+	})
+
+	t.Run("rejects unknown rules", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		code := Run(ctx, []string{"lint", "--source", "cli-test", "--severity", "pk=off"}, nil, &stdout, &stderr)
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "Error: unknown rule \"pk\"\n", stderr.String())
+	})
+}
+
+func TestRunConfig(t *testing.T) {
+	t.Setenv("DBERD_TEST_DSN", "clitest://localhost/app")
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dberd.yaml")
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "schema"), 0o700))
+
+	err := os.WriteFile(path, []byte(`
+sources:
+  - name: app
+    dsn: ${DBERD_TEST_DSN}
+    transforms:
+      strip_namespace: "*"
+    targets:
+      - target: cli-test
+        format_to_file: app.txt
+        render_to_file: app.svg
+      - target: cli-test
+        format_to_file: schema/app.txt
+  - name: broken
+    source: cli-test
+    dsn: clitest://localhost/broken
+    options:
+      dangling: true
+    targets:
+      - target: cli-test
+        format_to_file: broken.txt
+`), 0o600)
+	require.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+
+	code := Run(ctx, []string{"run", "-c", path}, nil, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "Error: source broken: validating schema: dangling reference: "+
+		"public.users.id -> public.accounts.id, unknown table public.accounts\n", stderr.String())
+
+	data, err := os.ReadFile(filepath.Join(dir, "app.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "users", string(data))
+
+	data, err = os.ReadFile(filepath.Join(dir, "app.svg"))
+	require.NoError(t, err)
+	assert.Equal(t, "<svg>users ~1 rows</svg>", string(data))
+
+	data, err = os.ReadFile(filepath.Join(dir, "schema", "app.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "users", string(data))
+
+	assert.NoFileExists(t, filepath.Join(dir, "broken.txt"))
+
+	t.Run("rejects invalid config", func(t *testing.T) {
+		tests := map[string]string{
+			"sources:\n  - name: app\n    dns: clitest://\n": "Error: decoding config: yaml: unmarshal errors:\n" +
+				"  line 3: field dns not found in type cli.configSource\n",
+			"sources:\n  - name: app\n    dsn: ${DBERD_TEST_MISSING}\n": "Error: source app: environment variables not set: DBERD_TEST_MISSING\n",
+			"sources:\n  - name: app\n    dsn: clitest://\n":            "Error: source app: no targets\n",
+			"sources: []\n": "Error: config declares no sources\n",
+		}
+
+		for config, expected := range tests {
+			require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+
+			var stdout, stderr bytes.Buffer
+
+			code := Run(ctx, []string{"run", "--config", path}, nil, &stdout, &stderr)
+			assert.Equal(t, 1, code)
+			assert.Equal(t, expected, stderr.String())
+		}
+	})
+}
+
+func TestRunWatch(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dsn := t.Name()
+	out := filepath.Join(t.TempDir(), "schema.txt")
+
+	users := dberd.Table{Name: "public.users", Columns: []dberd.Column{{Name: "id", Definition: "INT8"}}}
+	watchSchemas.Store(dsn, dberd.Schema{Tables: []dberd.Table{users}})
+
+	var stdout, stderr syncBuffer
+
+	code := make(chan int)
+
+	go func() {
+		code <- Run(ctx, []string{
+			"watch", "--source", "cli-watch", "--source-dsn", dsn, "--target", "cli-test",
+			"--format-to-file", out, "--interval", "10ms",
+		}, nil, &stdout, &stderr)
+	}()
+
+	read := func() string {
+		data, _ := os.ReadFile(out)
+		return string(data)
+	}
+
+	require.Eventually(t, func() bool { return read() == "public.users" }, time.Second, 10*time.Millisecond)
+
+	accounts := dberd.Table{Name: "public.accounts", Columns: []dberd.Column{{Name: "id", Definition: "INT8"}}}
+	watchSchemas.Store(dsn, dberd.Schema{Tables: []dberd.Table{accounts, users}})
+
+	require.Eventually(t, func() bool { return read() == "public.accounts" }, time.Second, 10*time.Millisecond)
+
+	// Reordered tables are the same schema, so the outputs are not regenerated.
+	watchSchemas.Store(dsn, dberd.Schema{Tables: []dberd.Table{users, accounts}})
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	assert.Equal(t, 0, <-code)
+
+	assert.Equal(t, "public.accounts", read())
+	assert.Equal(t, 1, strings.Count(stderr.String(), "schema changed"), stderr.String())
+	assert.Contains(t, stderr.String(), "cli-watch: generating outputs\n")
+	assert.Contains(t, stderr.String(), "cli-watch:   + table public.accounts\n")
+}
+
+func TestSchemaChanges(t *testing.T) {
+	t.Parallel()
+
+	old := dberd.Schema{
+		Tables: []dberd.Table{
+			{
+				Name: "public.users",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8", IsPrimary: true},
+					{Name: "email", Definition: "TEXT"},
+					{Name: "nickname", Definition: "TEXT"},
+				},
+			},
+			{Name: "public.sessions", Columns: []dberd.Column{{Name: "user_id", Definition: "INT8"}}},
+		},
+		References: []dberd.Reference{
+			{
+				Source: dberd.TableColumn{Table: "public.sessions", Column: "user_id"},
+				Target: dberd.TableColumn{Table: "public.users", Column: "id"},
+			},
+		},
+	}
+
+	current := dberd.Schema{
+		Tables: []dberd.Table{
+			{
+				Name: "public.users",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8", IsPrimary: true, Comment: "User ID"},
+					{Name: "email", Definition: "VARCHAR(255)"},
+				},
+				Indexes: []dberd.Index{{Name: "users_email_idx", Columns: []string{"email"}}},
+			},
+			{Name: "public.orders", Columns: []dberd.Column{{Name: "user_id", Definition: "INT8"}}},
+		},
+		References: []dberd.Reference{
+			{
+				Source: dberd.TableColumn{Table: "public.orders", Column: "user_id"},
+				Target: dberd.TableColumn{Table: "public.users", Column: "id"},
+			},
+		},
+	}
+
+	expected := []string{
+		"~ column public.users.id",
+		"~ column public.users.email: TEXT -> VARCHAR(255)",
+		"- column public.users.nickname",
+		"+ index users_email_idx on public.users",
+		"+ table public.orders",
+		"- table public.sessions",
+		"+ reference public.orders.user_id -> public.users.id",
+		"- reference public.sessions.user_id -> public.users.id",
+	}
+
+	assert.Equal(t, expected, schemaChanges(old, current))
+	assert.Equal(t, []string{"~ comments, constraints, grants or routines"}, schemaChanges(old, old))
+}
+
+func TestCanonicalCopy(t *testing.T) {
+	t.Parallel()
+
+	schema := dberd.Schema{
+		Tables: []dberd.Table{
+			{Name: "public.users", Columns: []dberd.Column{{Name: "id", Definition: "INT8"}}, Stats: &dberd.TableStats{Rows: 10}},
+		},
+		Metadata: dberd.NewMetadata("postgres", "16.2", "app"),
+	}
+
+	old, err := canonicalCopy(schema)
+	require.NoError(t, err)
+
+	schema.Tables[0].Stats = &dberd.TableStats{Rows: 20}
+
+	current, err := canonicalCopy(schema)
+	require.NoError(t, err)
+	assert.Equal(t, old, current, "metadata and stats must be ignored")
+	assert.Nil(t, current.Tables[0].Stats)
+	assert.NotNil(t, schema.Metadata, "the schema must not be modified")
+}
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+
+	dsn := t.Name()
+	users := dberd.Table{Name: "public.users", Columns: []dberd.Column{{Name: "id", Definition: "INT8"}}}
+	accounts := dberd.Table{Name: "public.accounts", Columns: []dberd.Column{{Name: "id", Definition: "INT8"}}}
+
+	watchSchemas.Store(dsn, dberd.Schema{Tables: []dberd.Table{users}})
+
+	var logs syncBuffer
+
+	cfgs := []config{
+		{
+			name:        "app",
+			sourceType:  "cli-watch",
+			sourceDSN:   dsn,
+			transformer: dberd.Pipeline{},
+			targets:     []targetConfig{{targetType: "cli-test"}},
+			stderr:      &logs,
+		},
+		{
+			name:        "broken",
+			sourceType:  "cli-watch",
+			sourceDSN:   dsn + "/missing",
+			transformer: dberd.Pipeline{},
+			targets:     []targetConfig{{targetType: "cli-test"}},
+			stderr:      &logs,
+		},
+	}
+
+	srv, err := newServer(context.Background(), cfgs, time.Minute, log.New(&logs, "", 0))
+	require.NoError(t, err)
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	srv.now = func() time.Time { return now }
+
+	ts := httptest.NewServer(srv.handler())
+	defer ts.Close()
+
+	get := func(path string) (int, string) {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, ts.URL+path, nil)
+		require.NoError(t, err)
+
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get("/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `<a href="/sources/app/schema.svg">diagram</a>`)
+	assert.Contains(t, body, `<form method="post" action="/sources/broken/refresh">`)
+	assert.Contains(t, body, "not yet")
+
+	code, body = get("/sources/app/schema.svg")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "<svg>public.users</svg>", body)
+
+	code, body = get("/sources/app/schema.txt")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "public.users", body)
+
+	code, body = get("/sources/app/schema.json")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `"name": "public.users"`)
+
+	code, body = get("/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "2025-01-02 03:04:05 UTC")
+
+	// The cached schema is served until its TTL expires.
+	watchSchemas.Store(dsn, dberd.Schema{Tables: []dberd.Table{accounts, users}})
+
+	_, body = get("/sources/app/schema.txt")
+	assert.Equal(t, "public.users", body)
+
+	now = now.Add(time.Minute)
+
+	_, body = get("/sources/app/schema.txt")
+	assert.Equal(t, "public.accounts", body)
+
+	// Refreshing extracts the schema regardless of its TTL.
+	watchSchemas.Store(dsn, dberd.Schema{Tables: []dberd.Table{users}})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, ts.URL+"/sources/app/refresh", nil)
+	require.NoError(t, err)
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "/", resp.Request.URL.Path)
+
+	_, body = get("/sources/app/schema.txt")
+	assert.Equal(t, "public.users", body)
+
+	code, body = get("/sources/broken/schema.svg")
+	assert.Equal(t, http.StatusBadGateway, code)
+	assert.NotContains(t, body, "missing")
+	assert.Contains(t, logs.String(), "broken: extracting schema: ")
+
+	code, _ = get("/sources/unknown/schema.svg")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = get("/sources/app/schema.png")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	var stderr bytes.Buffer
+
+	assert.Equal(t, 1, Run(context.Background(), []string{"serve"}, nil, &bytes.Buffer{}, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: dberd serve [options]")
+}
+
+func TestServeIndexDuringExtraction(t *testing.T) {
+	t.Parallel()
+
+	cfgs := []config{{
+		name:        "app",
+		sourceType:  "cli-watch",
+		sourceDSN:   t.Name(),
+		transformer: dberd.Pipeline{},
+		targets:     []targetConfig{{targetType: "cli-test"}},
+		stderr:      io.Discard,
+	}}
+
+	srv, err := newServer(context.Background(), cfgs, time.Minute, log.New(io.Discard, "", 0))
+	require.NoError(t, err)
+
+	// An extraction in progress must not block the index page.
+	srv.sources[0].extracting.Lock()
+	defer srv.sources[0].extracting.Unlock()
+
+	rec := httptest.NewRecorder()
+	srv.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "not yet")
+}
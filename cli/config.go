@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/holydocs/dberd"
+	"gopkg.in/yaml.v3"
+)
+
+// configFile is the dberd.yaml config file, declaring sources and the targets formatting their schemas, e.g.
+//
+//	sources:
+//	  - name: app
+//	    dsn: ${APP_DATABASE_URL}
+//	    options:
+//	      stats: true
+//	    transforms:
+//	      exclude_tables: public\.schema_migrations
+//	      strip_namespace: public
+//	    targets:
+//	      - target: d2
+//	        format_to_file: docs/app.d2
+//	        render_to_file: docs/app.svg
+//	      - target: json
+//	        format_to_file: docs/app.json
+type configFile struct {
+	Sources []configSource `yaml:"sources"`
+}
+
+// configSource is a source declared in the config file. Its schema is extracted once for all its targets.
+type configSource struct {
+	Name       string            `yaml:"name"`
+	Source     string            `yaml:"source"`
+	DSN        string            `yaml:"dsn"`
+	Options    map[string]string `yaml:"options"`
+	Transforms transforms        `yaml:"transforms"`
+	Targets    []configTarget    `yaml:"targets"`
+}
+
+// configTarget is a target declared in the config file with its outputs.
+type configTarget struct {
+	Target       string            `yaml:"target"`
+	Options      map[string]string `yaml:"options"`
+	FormatToFile string            `yaml:"format_to_file"`
+	RenderToFile string            `yaml:"render_to_file"`
+}
+
+// loadConfig reads the config file and returns a config per declared source.
+// Sources without targets are allowed, as the serve command doesn't use them.
+// Environment variables in DSNs, e.g. ${APP_DATABASE_URL}, are expanded and relative
+// output paths are resolved against the config file directory.
+func loadConfig(path string) ([]config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	var file configFile
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	err = dec.Decode(&file)
+	if err != nil {
+		return nil, fmt.Errorf("decoding config: %w", err)
+	}
+
+	if len(file.Sources) == 0 {
+		return nil, errors.New("config declares no sources")
+	}
+
+	dir := filepath.Dir(path)
+	names := make(map[string]bool, len(file.Sources))
+	cfgs := make([]config, 0, len(file.Sources))
+
+	for i, source := range file.Sources {
+		if source.Name == "" {
+			return nil, fmt.Errorf("source %d: name is required", i)
+		}
+
+		if names[source.Name] {
+			return nil, fmt.Errorf("source %s: declared more than once", source.Name)
+		}
+
+		names[source.Name] = true
+
+		cfg, err := source.config(dir)
+		if err != nil {
+			return nil, fmt.Errorf("source %s: %w", source.Name, err)
+		}
+
+		cfgs = append(cfgs, cfg)
+	}
+
+	return cfgs, nil
+}
+
+// config returns the run config of the source.
+func (s configSource) config(dir string) (config, error) {
+	dsn, err := expandEnv(s.DSN)
+	if err != nil {
+		return config{}, err
+	}
+
+	if dsn == "" {
+		return config{}, errors.New("dsn is required")
+	}
+
+	transformer, err := s.Transforms.pipeline()
+	if err != nil {
+		return config{}, err
+	}
+
+	cfg := config{
+		name:        s.Name,
+		sourceType:  s.Source,
+		sourceDSN:   dsn,
+		sourceOpts:  dberd.Options(s.Options),
+		transformer: transformer,
+	}
+
+	for _, target := range s.Targets {
+		if target.Target == "" {
+			return config{}, errors.New("target is required")
+		}
+
+		if target.FormatToFile == "" && target.RenderToFile == "" {
+			return config{}, fmt.Errorf("target %s: format_to_file or render_to_file is required", target.Target)
+		}
+
+		cfg.targets = append(cfg.targets, targetConfig{
+			targetType:   target.Target,
+			targetOpts:   dberd.Options(target.Options),
+			formatToFile: resolvePath(dir, target.FormatToFile),
+			renderToFile: resolvePath(dir, target.RenderToFile),
+		})
+	}
+
+	return cfg, nil
+}
+
+// expandEnv replaces ${var} or $var in the string with environment variable values.
+// Unlike os.ExpandEnv, it fails on unset variables, so a missing secret doesn't end up as an empty DSN part.
+func expandEnv(s string) (string, error) {
+	var missing []string
+
+	expanded := os.Expand(s, func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variables not set: %s", strings.Join(missing, ", "))
+	}
+
+	return expanded, nil
+}
+
+// resolvePath resolves the relative output path against the config file directory.
+func resolvePath(dir, path string) string {
+	if path == "" || path == stdio || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// diffContext is the number of unchanged lines shown around changes.
+	diffContext = 2
+	// diffMaxLines limits the diff output, so drift in large schemas stays readable.
+	diffMaxLines = 60
+	// diffMaxCells limits the LCS table size, larger changes are shown as a single replaced block.
+	diffMaxCells = 4 << 20
+)
+
+// diffOp is a diff line operation: ' ' for unchanged, '-' for removed and '+' for added lines.
+type diffOp struct {
+	kind byte
+	line string
+	// a and b are 1-based line numbers in the old and new texts.
+	a, b int
+}
+
+// writeDiff writes a concise unified diff of the old and new texts to w.
+func writeDiff(w io.Writer, oldName, newName, oldText, newText string) {
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", oldName, newName)
+
+	var written int
+
+	for _, hunk := range hunks(ops) {
+		first := hunk[0]
+		fmt.Fprintf(w, "@@ -%d +%d @@\n", first.a, first.b)
+
+		for _, op := range hunk {
+			if written == diffMaxLines {
+				fmt.Fprintf(w, "... diff truncated\n")
+				return
+			}
+
+			fmt.Fprintf(w, "%c%s\n", op.kind, op.line)
+			written++
+		}
+	}
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns line operations turning a into b, based on the longest common subsequence
+// of the lines left after trimming the common prefix and suffix.
+func diffLines(a, b []string) []diffOp {
+	var prefix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	var suffix int
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+
+	for i := range prefix {
+		ops = append(ops, diffOp{kind: ' ', line: a[i], a: i + 1, b: i + 1})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	ai, bi := prefix, prefix
+
+	emit := func(kind byte, line string) {
+		ops = append(ops, diffOp{kind: kind, line: line, a: ai + 1, b: bi + 1})
+
+		switch kind {
+		case '-':
+			ai++
+		case '+':
+			bi++
+		default:
+			ai++
+			bi++
+		}
+	}
+
+	if (len(midA)+1)*(len(midB)+1) > diffMaxCells {
+		for _, line := range midA {
+			emit('-', line)
+		}
+
+		for _, line := range midB {
+			emit('+', line)
+		}
+	} else {
+		// lcs[i][j] is the LCS length of midA[i:] and midB[j:].
+		lcs := make([][]int, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(midB)+1)
+		}
+
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+
+		i, j := 0, 0
+		for i < len(midA) || j < len(midB) {
+			switch {
+			case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+				emit(' ', midA[i])
+				i++
+				j++
+			case i < len(midA) && (j == len(midB) || lcs[i+1][j] >= lcs[i][j+1]):
+				emit('-', midA[i])
+				i++
+			default:
+				emit('+', midB[j])
+				j++
+			}
+		}
+	}
+
+	for i := len(a) - suffix; i < len(a); i++ {
+		emit(' ', a[i])
+	}
+
+	return ops
+}
+
+// hunks groups changed operations with their surrounding context lines.
+func hunks(ops []diffOp) [][]diffOp {
+	var (
+		result [][]diffOp
+		start  = -1
+		end    int
+	)
+
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+
+		from := max(i-diffContext, 0)
+		if start >= 0 && from > end {
+			result = append(result, ops[start:end])
+			start = -1
+		}
+
+		if start < 0 {
+			start = from
+		}
+
+		end = min(i+diffContext+1, len(ops))
+	}
+
+	if start >= 0 {
+		result = append(result, ops[start:end])
+	}
+
+	return result
+}
@@ -5,16 +5,74 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"regexp"
 	"sort"
+	"strings"
+	"time"
 )
 
+// Version is the dberd version recorded in the extracted schema metadata.
+// It is meant to be set at build time, e.g. -ldflags "-X github.com/holydocs/dberd.Version=v1.0.0".
+var Version = "dev"
+
 // TargetType represents the type of language for describing database schema.
 type TargetType string
 
 // Schema represents a complete database schema with tables and their references.
 type Schema struct {
-	Tables     []Table     `json:"tables"`
-	References []Reference `json:"references"`
+	Tables     []Table          `json:"tables"`
+	References []Reference      `json:"references"`
+	Regions    *DatabaseRegions `json:"regions,omitempty"`
+	Routines   *Routines        `json:"routines,omitempty"`
+	Grants     []Grant          `json:"grants,omitempty"`
+	Metadata   *Metadata        `json:"metadata,omitempty"`
+}
+
+// Metadata represents the schema provenance: where and when it was extracted from.
+type Metadata struct {
+	Source        string    `json:"source"`
+	ServerVersion string    `json:"server_version,omitempty"`
+	Database      string    `json:"database,omitempty"`
+	ExtractedAt   time.Time `json:"extracted_at"`
+	Version       string    `json:"version"`
+}
+
+// NewMetadata creates schema metadata extracted at the current time by the current dberd version.
+func NewMetadata(source, serverVersion, database string) *Metadata {
+	return &Metadata{
+		Source:        source,
+		ServerVersion: serverVersion,
+		Database:      database,
+		ExtractedAt:   time.Now().UTC().Truncate(time.Second),
+		Version:       Version,
+	}
+}
+
+// Title returns a human-readable metadata summary, which targets may display as a diagram title,
+// e.g. "postgres 16.2, database app, extracted at 2025-01-02T03:04:05Z by dberd v1.0.0".
+func (m Metadata) Title() string {
+	title := m.Source
+	if m.ServerVersion != "" {
+		title += " " + m.ServerVersion
+	}
+	if m.Database != "" {
+		title += ", database " + m.Database
+	}
+	if !m.ExtractedAt.IsZero() {
+		title += ", extracted at " + m.ExtractedAt.UTC().Format(time.RFC3339)
+	}
+	if m.Version != "" {
+		title += " by dberd " + m.Version
+	}
+
+	return title
+}
+
+// DatabaseRegions represents multi-region configuration of a database.
+type DatabaseRegions struct {
+	Primary      string   `json:"primary"`
+	Regions      []string `json:"regions"`
+	SurvivalGoal string   `json:"survival_goal,omitempty"`
 }
 
 // Sort sorts the schema's tables and references in a consistent order.
@@ -24,6 +82,25 @@ func (s *Schema) Sort() {
 	})
 
 	for i := range s.Tables {
+		sort.Slice(s.Tables[i].Indexes, func(j, k int) bool {
+			return s.Tables[i].Indexes[j].Name < s.Tables[i].Indexes[k].Name
+		})
+
+		if s.Tables[i].Partitioning != nil {
+			partitions := s.Tables[i].Partitioning.Partitions
+			sort.Slice(partitions, func(j, k int) bool {
+				return partitions[j].Name < partitions[k].Name
+			})
+		}
+
+		sort.Slice(s.Tables[i].Policies, func(j, k int) bool {
+			return s.Tables[i].Policies[j].Name < s.Tables[i].Policies[k].Name
+		})
+
+		sort.Slice(s.Tables[i].Checks, func(j, k int) bool {
+			return s.Tables[i].Checks[j].Name < s.Tables[i].Checks[k].Name
+		})
+
 		sort.Slice(s.Tables[i].Columns, func(j, k int) bool {
 			if s.Tables[i].Columns[j].IsPrimary != s.Tables[i].Columns[k].IsPrimary {
 				return s.Tables[i].Columns[j].IsPrimary
@@ -35,6 +112,17 @@ func (s *Schema) Sort() {
 		})
 	}
 
+	if s.Routines != nil {
+		s.Routines.sort()
+	}
+
+	sort.Slice(s.Grants, func(i, j int) bool {
+		if s.Grants[i].Table != s.Grants[j].Table {
+			return s.Grants[i].Table < s.Grants[j].Table
+		}
+		return s.Grants[i].Role < s.Grants[j].Role
+	})
+
 	sort.Slice(s.References, func(i, j int) bool {
 		switch {
 		case s.References[i].Source.Table != s.References[j].Source.Table:
@@ -49,18 +137,366 @@ func (s *Schema) Sort() {
 	})
 }
 
+// ComputeCardinality sets cardinality and optionality of foreign key references
+// from the source column uniqueness and nullability, see Column.Nullable. A source column
+// is unique when it is the only primary key column or the only column of a unique index.
+// References with an unknown source table or column and many-to-many references are left untouched.
+func (s *Schema) ComputeCardinality() {
+	tables := make(map[string]*Table, len(s.Tables))
+	for i := range s.Tables {
+		tables[s.Tables[i].Name] = &s.Tables[i]
+	}
+
+	for i := range s.References {
+		ref := &s.References[i]
+		if ref.Kind != ReferenceKindForeignKey || ref.IsManyToMany() {
+			continue
+		}
+
+		table, ok := tables[ref.Source.Table]
+		if !ok {
+			continue
+		}
+
+		column, ok := table.Column(ref.Source.Column)
+		if !ok {
+			continue
+		}
+
+		ref.Cardinality = CardinalityManyToOne
+		if table.IsUniqueColumn(column.Name) {
+			ref.Cardinality = CardinalityOneToOne
+		}
+
+		ref.Optional = column.Nullable()
+	}
+}
+
+// Routines represents an optional schema section with sequences, functions and triggers.
+type Routines struct {
+	Sequences []Sequence `json:"sequences,omitempty"`
+	Functions []Function `json:"functions,omitempty"`
+	Triggers  []Trigger  `json:"triggers,omitempty"`
+}
+
+// sort sorts sequences, functions and triggers in a consistent order.
+func (r *Routines) sort() {
+	sort.Slice(r.Sequences, func(i, j int) bool {
+		return r.Sequences[i].Name < r.Sequences[j].Name
+	})
+
+	sort.Slice(r.Functions, func(i, j int) bool {
+		if r.Functions[i].Name != r.Functions[j].Name {
+			return r.Functions[i].Name < r.Functions[j].Name
+		}
+		return r.Functions[i].Arguments < r.Functions[j].Arguments
+	})
+
+	sort.Slice(r.Triggers, func(i, j int) bool {
+		if r.Triggers[i].Table != r.Triggers[j].Table {
+			return r.Triggers[i].Table < r.Triggers[j].Table
+		}
+		return r.Triggers[i].Name < r.Triggers[j].Name
+	})
+}
+
+// Sequence represents a database sequence, optionally owned by a table column.
+type Sequence struct {
+	Name    string       `json:"name"`
+	Type    string       `json:"type,omitempty"`
+	OwnedBy *TableColumn `json:"owned_by,omitempty"`
+}
+
+// Function represents a database function or procedure.
+type Function struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments,omitempty"`
+	Returns   string `json:"returns,omitempty"`
+	Language  string `json:"language,omitempty"`
+}
+
+// Signature returns the function name with its arguments, e.g. "public.audit(text)",
+// which tells overloaded functions apart.
+func (f Function) Signature() string {
+	return f.Name + "(" + f.Arguments + ")"
+}
+
+// Trigger represents a table trigger, which links a table to the function it executes.
+// Function is the signature of the executed function, see Function.Signature.
+type Trigger struct {
+	Name     string   `json:"name"`
+	Table    string   `json:"table"`
+	Function string   `json:"function"`
+	Timing   string   `json:"timing"`
+	Events   []string `json:"events"`
+	ForEach  string   `json:"for_each"`
+}
+
+// Grant represents table privileges granted to a role.
+type Grant struct {
+	Role       string   `json:"role"`
+	Table      string   `json:"table"`
+	Privileges []string `json:"privileges"`
+}
+
 // Table represents a database table with its columns.
+// Locality is a multi-region table locality, e.g. "REGIONAL BY ROW" or "GLOBAL",
+// and PrimaryKeyShardBuckets is a number of hash-sharded primary key buckets, 0 if not sharded.
+// RowSecurity reports whether row-level security is enabled, with Policies restricting row access.
+// ForeignServer is a name of the server a foreign table data comes from, empty for regular tables.
+// Stats holds optional size estimates, extracted only when requested from the source.
 type Table struct {
-	Name    string   `json:"name"`
-	Columns []Column `json:"columns"`
+	Name                   string            `json:"name"`
+	Columns                []Column          `json:"columns"`
+	Indexes                []Index           `json:"indexes,omitempty"`
+	Checks                 []CheckConstraint `json:"checks,omitempty"`
+	Engine                 string            `json:"engine,omitempty"`
+	Collation              string            `json:"collation,omitempty"`
+	Locality               string            `json:"locality,omitempty"`
+	PrimaryKeyShardBuckets int               `json:"primary_key_shard_buckets,omitempty"`
+	Partitioning           *Partitioning     `json:"partitioning,omitempty"`
+	RowSecurity            bool              `json:"row_security,omitempty"`
+	Policies               []Policy          `json:"policies,omitempty"`
+	ForeignServer          string            `json:"foreign_server,omitempty"`
+	Stats                  *TableStats       `json:"stats,omitempty"`
+}
+
+// TableStats represents estimated table statistics.
+// Rows is an estimated number of rows (documents) and SizeBytes is an estimated on-disk size
+// including indexes, 0 if the source can't estimate it cheaply.
+type TableStats struct {
+	Rows      int64 `json:"rows"`
+	SizeBytes int64 `json:"size_bytes,omitempty"`
+}
+
+// Badge returns a short human-readable statistics summary, e.g. "~1.2M rows, 350.0 MiB".
+// The size is omitted when unknown.
+func (s TableStats) Badge() string {
+	if s.SizeBytes == 0 {
+		return fmt.Sprintf("~%s rows", formatCount(s.Rows))
+	}
+
+	return fmt.Sprintf("~%s rows, %s", formatCount(s.Rows), formatBytes(s.SizeBytes))
+}
+
+// formatCount formats a count with a metric suffix, e.g. 1200000 as "1.2M".
+func formatCount(n int64) string {
+	const unit = 1000
+
+	if n < unit {
+		return fmt.Sprintf("%d", n)
+	}
+
+	value, suffix := float64(n), ""
+	for _, s := range []string{"K", "M", "B", "T"} {
+		if value < unit {
+			break
+		}
+		value /= unit
+		suffix = s
+	}
+
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0") + suffix
+}
+
+// formatBytes formats a size in bytes with a binary suffix, e.g. 1536 as "1.5 KiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	value, suffix := float64(n), ""
+	for _, s := range []string{"KiB", "MiB", "GiB", "TiB", "PiB"} {
+		if value < unit {
+			break
+		}
+		value /= unit
+		suffix = s
+	}
+
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
+
+// Policy represents a row-level security policy of a table.
+// Using filters rows visible to the command, Check validates new rows.
+type Policy struct {
+	Name       string   `json:"name"`
+	Command    string   `json:"command"`
+	Roles      []string `json:"roles"`
+	Using      string   `json:"using,omitempty"`
+	Check      string   `json:"check,omitempty"`
+	Permissive bool     `json:"permissive"`
+}
+
+// Column returns the table column with the given name.
+func (t Table) Column(name string) (Column, bool) {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+
+	return Column{}, false
+}
+
+// IsUniqueColumn reports whether the column alone identifies a table row: it is the only
+// primary key column or the only column of a unique index.
+func (t Table) IsUniqueColumn(name string) bool {
+	var primaryColumns []string
+	for _, column := range t.Columns {
+		if column.IsPrimary {
+			primaryColumns = append(primaryColumns, column.Name)
+		}
+	}
+
+	if len(primaryColumns) == 1 && primaryColumns[0] == name {
+		return true
+	}
+
+	for _, index := range t.Indexes {
+		if (index.IsUnique || index.IsPrimary) && len(index.Columns) == 1 && index.Columns[0] == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Partitioning represents how a partitioned table is split into partitions.
+// Partitions are folded into their parent table and listed only when requested from the source.
+type Partitioning struct {
+	Strategy   string      `json:"strategy"`
+	Key        string      `json:"key"`
+	Partitions []Partition `json:"partitions,omitempty"`
+}
+
+// Partition represents a single partition of a partitioned table.
+type Partition struct {
+	Name  string `json:"name"`
+	Bound string `json:"bound,omitempty"`
+}
+
+// Annotations returns human-readable table annotations, such as locality or sharding,
+// which targets may display next to the table.
+func (t Table) Annotations() []string {
+	var annotations []string
+
+	if t.Locality != "" {
+		annotations = append(annotations, t.Locality)
+	}
+	if t.PrimaryKeyShardBuckets > 0 {
+		annotations = append(annotations, fmt.Sprintf("HASH-SHARDED PK (%d buckets)", t.PrimaryKeyShardBuckets))
+	}
+	if t.Partitioning != nil {
+		annotations = append(annotations, fmt.Sprintf("PARTITION BY %s (%s)", t.Partitioning.Strategy, t.Partitioning.Key))
+	}
+	if t.ForeignServer != "" {
+		annotations = append(annotations, fmt.Sprintf("FOREIGN TABLE (%s)", t.ForeignServer))
+	}
+	if t.Stats != nil {
+		annotations = append(annotations, t.Stats.Badge())
+	}
+
+	return annotations
 }
 
 // Column represents a database table column.
+// NotNull is set only by sources that know column nullability, otherwise a column is considered nullable.
 type Column struct {
+	Name          string           `json:"name"`
+	Comment       string           `json:"comment,omitempty"`
+	Definition    string           `json:"definition"`
+	IsPrimary     bool             `json:"is_primary"`
+	NotNull       bool             `json:"not_null,omitempty"`
+	AutoIncrement bool             `json:"auto_increment,omitempty"`
+	Generated     *GeneratedColumn `json:"generated,omitempty"`
+	OnUpdate      string           `json:"on_update,omitempty"`
+}
+
+var (
+	// definitionClauses are column definition clauses following the column type.
+	definitionClauses = regexp.MustCompile(
+		`(?i)\s+(NOT NULL|NULL|DEFAULT|GENERATED|AUTO_INCREMENT|COLLATE|CHARACTER SET|ON UPDATE|PRIMARY KEY|UNIQUE|COMMENT|CODEC|MATERIALIZED|ALIAS)\b`)
+	// defaultClause is the column definition default clause.
+	defaultClause = regexp.MustCompile(`(?i)\s+DEFAULT\s+`)
+	// defaultEnd are column definition clauses sources append after the default value.
+	defaultEnd = regexp.MustCompile(`(?i)\s+(AUTO_INCREMENT|ON UPDATE|CODEC|COMMENT)\b`)
+	// notNullClause is the column definition NOT NULL clause.
+	notNullClause = regexp.MustCompile(`(?i)\sNOT NULL\b`)
+)
+
+// Type returns the column type parsed from its definition as written,
+// e.g. "VARCHAR(255)" for "VARCHAR(255) NOT NULL DEFAULT 'guest'".
+func (c Column) Type() string {
+	columnType, _ := c.splitDefinition()
+	return columnType
+}
+
+// Default returns the column default value parsed from its definition, e.g. "'guest'"
+// for "VARCHAR(255) NOT NULL DEFAULT 'guest'", or an empty string if it has none.
+func (c Column) Default() string {
+	_, clauses := c.splitDefinition()
+
+	loc := defaultClause.FindStringIndex(clauses)
+	if loc == nil {
+		return ""
+	}
+
+	value := clauses[loc[1]:]
+	if end := defaultEnd.FindStringIndex(value); end != nil {
+		value = value[:end[0]]
+	}
+
+	return value
+}
+
+// Nullable reports whether the column accepts NULL values. Primary key columns, NotNull columns
+// and columns with a NOT NULL clause in their definition don't.
+func (c Column) Nullable() bool {
+	if c.NotNull || c.IsPrimary {
+		return false
+	}
+
+	// Clauses preceding the default value are checked for NOT NULL, so a default value can't match.
+	_, clauses := c.splitDefinition()
+	if loc := defaultClause.FindStringIndex(clauses); loc != nil {
+		clauses = clauses[:loc[0]]
+	}
+
+	return !notNullClause.MatchString(clauses)
+}
+
+// splitDefinition splits the column definition into the column type and the clauses following it.
+func (c Column) splitDefinition() (columnType, clauses string) {
+	loc := definitionClauses.FindStringIndex(c.Definition)
+	if loc == nil {
+		return c.Definition, ""
+	}
+
+	return c.Definition[:loc[0]], c.Definition[loc[0]:]
+}
+
+// GeneratedColumn represents the expression a generated column value is computed from.
+type GeneratedColumn struct {
+	Expression string `json:"expression"`
+	Stored     bool   `json:"stored"`
+}
+
+// CheckConstraint represents a table check constraint.
+type CheckConstraint struct {
 	Name       string `json:"name"`
-	Comment    string `json:"comment,omitempty"`
-	Definition string `json:"definition"`
-	IsPrimary  bool   `json:"is_primary"`
+	Expression string `json:"expression"`
+}
+
+// Index represents a table index.
+type Index struct {
+	Name      string   `json:"name"`
+	Columns   []string `json:"columns"`
+	Type      string   `json:"type,omitempty"`
+	IsUnique  bool     `json:"is_unique,omitempty"`
+	IsPrimary bool     `json:"is_primary,omitempty"`
 }
 
 // TableColumn represents a reference to a specific column in a table.
@@ -69,10 +505,123 @@ type TableColumn struct {
 	Column string `json:"column"`
 }
 
+// ReferenceKind represents a kind of relationship between two tables.
+type ReferenceKind string
+
+const (
+	// ReferenceKindForeignKey is a foreign key relationship between two table columns.
+	// It is the default kind of a reference with an empty Kind.
+	ReferenceKindForeignKey = ReferenceKind("")
+	// ReferenceKindInheritance is a table inheritance relationship, where the source table
+	// inherits columns of the target table. Columns of both sides are empty.
+	ReferenceKindInheritance = ReferenceKind("inheritance")
+)
+
+// Cardinality represents how many source table rows may reference a single target table row.
+type Cardinality string
+
+const (
+	// CardinalityManyToOne is a relationship where many source rows reference one target row.
+	CardinalityManyToOne = Cardinality("many_to_one")
+	// CardinalityOneToOne is a relationship where at most one source row references a target row,
+	// because the source column is unique.
+	CardinalityOneToOne = Cardinality("one_to_one")
+	// CardinalityManyToMany is a relationship where many source rows relate to many target rows
+	// through a junction table, see CollapseJunctionTables.
+	CardinalityManyToMany = Cardinality("many_to_many")
+)
+
+// Multiplicity represents how many rows may be at one end of a relationship, as drawn in crow's foot notation.
+type Multiplicity int
+
+const (
+	// MultiplicityZeroOrOne is an end with at most one row.
+	MultiplicityZeroOrOne Multiplicity = iota
+	// MultiplicityExactlyOne is an end with exactly one row.
+	MultiplicityExactlyOne
+	// MultiplicityZeroOrMany is an end with any number of rows.
+	MultiplicityZeroOrMany
+)
+
 // Reference represents a foreign key relationship between two table columns.
+// Cardinality and Optional are computed by Schema.ComputeCardinality, Optional reports
+// whether the source column is nullable, so the source row may reference no target row.
+// Via is a name of the junction table a many-to-many reference was collapsed from.
 type Reference struct {
-	Source TableColumn `json:"source"`
-	Target TableColumn `json:"target"`
+	Source      TableColumn   `json:"source"`
+	Target      TableColumn   `json:"target"`
+	Kind        ReferenceKind `json:"kind,omitempty"`
+	Name        string        `json:"name,omitempty"`
+	OnUpdate    string        `json:"on_update,omitempty"`
+	OnDelete    string        `json:"on_delete,omitempty"`
+	Cardinality Cardinality   `json:"cardinality,omitempty"`
+	Optional    bool          `json:"optional,omitempty"`
+	Via         string        `json:"via,omitempty"`
+}
+
+// IsInheritance reports whether the reference is a table inheritance relationship.
+func (r Reference) IsInheritance() bool {
+	return r.Kind == ReferenceKindInheritance
+}
+
+// String returns a human-readable reference description, e.g. "posts.user_id -> users.id"
+// or "admins inherits users".
+func (r Reference) String() string {
+	if r.IsInheritance() {
+		return r.Source.Table + " inherits " + r.Target.Table
+	}
+
+	return r.Source.Table + "." + r.Source.Column + " -> " + r.Target.Table + "." + r.Target.Column
+}
+
+// IsManyToMany reports whether the reference is a many-to-many relationship through a junction table.
+func (r Reference) IsManyToMany() bool {
+	return r.Cardinality == CardinalityManyToMany
+}
+
+// Multiplicities returns the source and target ends of the reference. References without computed
+// cardinality are many-to-one with a mandatory target, which matches a plain foreign key.
+func (r Reference) Multiplicities() (source, target Multiplicity) {
+	if r.IsManyToMany() {
+		return MultiplicityZeroOrMany, MultiplicityZeroOrMany
+	}
+
+	source = MultiplicityZeroOrMany
+	if r.Cardinality == CardinalityOneToOne {
+		source = MultiplicityZeroOrOne
+	}
+
+	target = MultiplicityExactlyOne
+	if r.Optional {
+		target = MultiplicityZeroOrOne
+	}
+
+	return source, target
+}
+
+// Actions returns a human-readable description of the reference referential actions,
+// e.g. "ON DELETE CASCADE". Default actions (NO ACTION, RESTRICT) are omitted.
+func (r Reference) Actions() string {
+	actions := make([]string, 0, 2)
+
+	if isNonDefaultAction(r.OnDelete) {
+		actions = append(actions, "ON DELETE "+r.OnDelete)
+	}
+	if isNonDefaultAction(r.OnUpdate) {
+		actions = append(actions, "ON UPDATE "+r.OnUpdate)
+	}
+
+	return strings.Join(actions, " ")
+}
+
+// isNonDefaultAction reports whether referential action changes the default behaviour.
+func isNonDefaultAction(action string) bool {
+	switch strings.ToUpper(action) {
+	case "", "NO ACTION", "RESTRICT":
+		return false
+	default:
+		return true
+	}
 }
 
 // FormattedSchema represents a formatted database schema.
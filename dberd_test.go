@@ -2,6 +2,7 @@ package dberd
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -259,3 +260,339 @@ func TestSchema_Sort(t *testing.T) {
 		})
 	}
 }
+
+func TestReference_Actions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		reference Reference
+		expected  string
+	}{
+		{
+			name:      "no actions",
+			reference: Reference{},
+			expected:  "",
+		},
+		{
+			name:      "default actions are omitted",
+			reference: Reference{OnUpdate: "NO ACTION", OnDelete: "RESTRICT"},
+			expected:  "",
+		},
+		{
+			name:      "on delete cascade",
+			reference: Reference{OnUpdate: "NO ACTION", OnDelete: "CASCADE"},
+			expected:  "ON DELETE CASCADE",
+		},
+		{
+			name:      "on delete and on update",
+			reference: Reference{OnUpdate: "CASCADE", OnDelete: "SET NULL"},
+			expected:  "ON DELETE SET NULL ON UPDATE CASCADE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.reference.Actions())
+		})
+	}
+}
+
+func TestColumn_Definition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		column       Column
+		expectedType string
+		defaultValue string
+		nullable     bool
+	}{
+		{
+			column:       Column{Definition: "INT8"},
+			expectedType: "INT8",
+			nullable:     true,
+		},
+		{
+			column:       Column{Definition: "INT8 NOT NULL DEFAULT nextval('users_id_seq'::regclass)"},
+			expectedType: "INT8",
+			defaultValue: "nextval('users_id_seq'::regclass)",
+		},
+		{
+			column:       Column{Definition: "varchar(20) DEFAULT 'NOT NULL'"},
+			expectedType: "varchar(20)",
+			defaultValue: "'NOT NULL'",
+			nullable:     true,
+		},
+		{
+			column:       Column{Definition: "int NOT NULL AUTO_INCREMENT", IsPrimary: true},
+			expectedType: "int",
+		},
+		{
+			column:       Column{Definition: "Nullable(String) DEFAULT '' CODEC(ZSTD)"},
+			expectedType: "Nullable(String)",
+			defaultValue: "''",
+			nullable:     true,
+		},
+		{
+			column:       Column{Definition: "TEXT", NotNull: true},
+			expectedType: "TEXT",
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expectedType, tt.column.Type(), tt.column.Definition)
+		assert.Equal(t, tt.defaultValue, tt.column.Default(), tt.column.Definition)
+		assert.Equal(t, tt.nullable, tt.column.Nullable(), tt.column.Definition)
+	}
+}
+
+func TestReference_String(t *testing.T) {
+	t.Parallel()
+
+	ref := Reference{Source: TableColumn{Table: "posts", Column: "user_id"}, Target: TableColumn{Table: "users", Column: "id"}}
+	assert.Equal(t, "posts.user_id -> users.id", ref.String())
+
+	ref = Reference{Source: TableColumn{Table: "admins"}, Target: TableColumn{Table: "users"}, Kind: ReferenceKindInheritance}
+	assert.Equal(t, "admins inherits users", ref.String())
+}
+
+func TestReference_Multiplicities(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		reference Reference
+		source    Multiplicity
+		target    Multiplicity
+	}{
+		{
+			name:      "unknown cardinality",
+			reference: Reference{},
+			source:    MultiplicityZeroOrMany,
+			target:    MultiplicityExactlyOne,
+		},
+		{
+			name:      "optional one to one",
+			reference: Reference{Cardinality: CardinalityOneToOne, Optional: true},
+			source:    MultiplicityZeroOrOne,
+			target:    MultiplicityZeroOrOne,
+		},
+		{
+			name:      "many to one",
+			reference: Reference{Cardinality: CardinalityManyToOne},
+			source:    MultiplicityZeroOrMany,
+			target:    MultiplicityExactlyOne,
+		},
+		{
+			name:      "many to many",
+			reference: Reference{Cardinality: CardinalityManyToMany, Optional: true},
+			source:    MultiplicityZeroOrMany,
+			target:    MultiplicityZeroOrMany,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, target := tt.reference.Multiplicities()
+			assert.Equal(t, tt.source, source)
+			assert.Equal(t, tt.target, target)
+		})
+	}
+}
+
+func TestTable_Annotations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		table    Table
+		expected []string
+	}{
+		{
+			name:     "no annotations",
+			table:    Table{Name: "public.users"},
+			expected: nil,
+		},
+		{
+			name:     "locality",
+			table:    Table{Name: "public.users", Locality: "REGIONAL BY ROW"},
+			expected: []string{"REGIONAL BY ROW"},
+		},
+		{
+			name:     "locality and hash-sharded primary key",
+			table:    Table{Name: "public.events", Locality: "GLOBAL", PrimaryKeyShardBuckets: 16},
+			expected: []string{"GLOBAL", "HASH-SHARDED PK (16 buckets)"},
+		},
+		{
+			name: "partitioning",
+			table: Table{
+				Name:         "public.events",
+				Partitioning: &Partitioning{Strategy: "RANGE", Key: "created_at"},
+			},
+			expected: []string{"PARTITION BY RANGE (created_at)"},
+		},
+		{
+			name:     "foreign table",
+			table:    Table{Name: "public.remote_users", ForeignServer: "legacy"},
+			expected: []string{"FOREIGN TABLE (legacy)"},
+		},
+		{
+			name:     "stats",
+			table:    Table{Name: "public.events", Stats: &TableStats{Rows: 1234567, SizeBytes: 367001600}},
+			expected: []string{"~1.2M rows, 350.0 MiB"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.table.Annotations())
+		})
+	}
+}
+
+func TestMetadata_Title(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		metadata Metadata
+		expected string
+	}{
+		{
+			name:     "source only",
+			metadata: Metadata{Source: "mongodb"},
+			expected: "mongodb",
+		},
+		{
+			name: "full metadata",
+			metadata: Metadata{
+				Source:        "postgres",
+				ServerVersion: "16.2",
+				Database:      "app",
+				ExtractedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+				Version:       "v1.0.0",
+			},
+			expected: "postgres 16.2, database app, extracted at 2025-01-02T03:04:05Z by dberd v1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.metadata.Title())
+		})
+	}
+}
+
+func TestNewMetadata(t *testing.T) {
+	t.Parallel()
+
+	before := time.Now().UTC().Truncate(time.Second)
+
+	metadata := NewMetadata("postgres", "16.2", "app")
+
+	assert.Equal(t, "postgres", metadata.Source)
+	assert.Equal(t, "16.2", metadata.ServerVersion)
+	assert.Equal(t, "app", metadata.Database)
+	assert.Equal(t, Version, metadata.Version)
+	assert.False(t, metadata.ExtractedAt.Before(before))
+	assert.Equal(t, time.UTC, metadata.ExtractedAt.Location())
+}
+
+func TestTableStats_Badge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		stats    TableStats
+		expected string
+	}{
+		{name: "empty", stats: TableStats{}, expected: "~0 rows"},
+		{name: "small", stats: TableStats{Rows: 999, SizeBytes: 1023}, expected: "~999 rows, 1023 B"},
+		{name: "thousands", stats: TableStats{Rows: 1000, SizeBytes: 1536}, expected: "~1K rows, 1.5 KiB"},
+		{name: "billions", stats: TableStats{Rows: 2500000000, SizeBytes: 5 << 40}, expected: "~2.5B rows, 5.0 TiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.stats.Badge())
+		})
+	}
+}
+
+func TestSchema_ComputeCardinality(t *testing.T) {
+	t.Parallel()
+
+	schema := Schema{
+		Tables: []Table{
+			{
+				Name: "public.users",
+				Columns: []Column{
+					{Name: "id", IsPrimary: true, NotNull: true},
+				},
+			},
+			{
+				Name: "public.profiles",
+				Columns: []Column{
+					{Name: "user_id", IsPrimary: true, NotNull: true},
+				},
+			},
+			{
+				Name: "public.passports",
+				Columns: []Column{
+					{Name: "id", IsPrimary: true, NotNull: true},
+					{Name: "user_id"},
+				},
+				Indexes: []Index{
+					{Name: "passports_user_id_key", Columns: []string{"user_id"}, IsUnique: true},
+				},
+			},
+			{
+				Name: "public.posts",
+				Columns: []Column{
+					{Name: "id", IsPrimary: true, NotNull: true},
+					{Name: "user_id", Definition: "INT8 NOT NULL"},
+					{Name: "editor_id"},
+				},
+				Indexes: []Index{
+					{Name: "posts_user_id_editor_id_key", Columns: []string{"user_id", "editor_id"}, IsUnique: true},
+				},
+			},
+			{
+				Name: "public.user_roles",
+				Columns: []Column{
+					{Name: "user_id", IsPrimary: true, NotNull: true},
+					{Name: "role_id", IsPrimary: true, NotNull: true},
+				},
+			},
+			{
+				Name: "public.admins",
+				Columns: []Column{
+					{Name: "id", IsPrimary: true, NotNull: true},
+				},
+			},
+		},
+		References: []Reference{
+			{Source: TableColumn{Table: "public.profiles", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}},
+			{Source: TableColumn{Table: "public.passports", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}},
+			{Source: TableColumn{Table: "public.posts", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}},
+			{Source: TableColumn{Table: "public.posts", Column: "editor_id"}, Target: TableColumn{Table: "public.users", Column: "id"}},
+			{Source: TableColumn{Table: "public.user_roles", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}},
+			{Source: TableColumn{Table: "public.missing", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}},
+			{Source: TableColumn{Table: "public.admins"}, Target: TableColumn{Table: "public.users"}, Kind: ReferenceKindInheritance},
+		},
+	}
+
+	schema.ComputeCardinality()
+
+	expected := []Reference{
+		{Source: TableColumn{Table: "public.profiles", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}, Cardinality: CardinalityOneToOne},
+		{Source: TableColumn{Table: "public.passports", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}, Cardinality: CardinalityOneToOne, Optional: true},
+		{Source: TableColumn{Table: "public.posts", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}, Cardinality: CardinalityManyToOne},
+		{Source: TableColumn{Table: "public.posts", Column: "editor_id"}, Target: TableColumn{Table: "public.users", Column: "id"}, Cardinality: CardinalityManyToOne, Optional: true},
+		{Source: TableColumn{Table: "public.user_roles", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}, Cardinality: CardinalityManyToOne},
+		{Source: TableColumn{Table: "public.missing", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}},
+		{Source: TableColumn{Table: "public.admins"}, Target: TableColumn{Table: "public.users"}, Kind: ReferenceKindInheritance},
+	}
+
+	assert.Equal(t, expected, schema.References)
+}
@@ -0,0 +1,163 @@
+// Package lint provides a rule engine reviewing database schemas for common design smells,
+// such as tables without a primary key or foreign keys without a supporting index.
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/holydocs/dberd"
+)
+
+// Severity represents how serious a finding is.
+type Severity string
+
+const (
+	// SeverityError is a finding which should fail a review.
+	SeverityError = Severity("error")
+	// SeverityWarning is a finding which should be fixed, but doesn't fail a review by default.
+	SeverityWarning = Severity("warning")
+	// SeverityInfo is an informational finding.
+	SeverityInfo = Severity("info")
+	// SeverityOff disables a rule.
+	SeverityOff = Severity("off")
+)
+
+// rank returns the severity order, higher is more serious.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtLeast reports whether the severity is as serious as the given one or more.
+func (s Severity) AtLeast(other Severity) bool {
+	return s.rank() >= other.rank() && s.rank() > 0
+}
+
+// ParseSeverity parses a severity name.
+func ParseSeverity(s string) (Severity, error) {
+	switch severity := Severity(s); severity {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return severity, nil
+	default:
+		return "", fmt.Errorf("unknown severity %q", s)
+	}
+}
+
+// Finding represents a single rule violation. Column is empty for table-level findings.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Table    string   `json:"table"`
+	Column   string   `json:"column,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Location returns the qualified name of the finding table or column, e.g. "public.users.email".
+func (f Finding) Location() string {
+	if f.Column == "" {
+		return f.Table
+	}
+
+	return f.Table + "." + f.Column
+}
+
+// Rule represents a lint rule. Check returns rule findings, their severity is set by the Linter.
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       func(s dberd.Schema) []Finding
+}
+
+// Linter checks schemas against a set of rules.
+type Linter struct {
+	rules      []Rule
+	severities map[string]Severity
+}
+
+// LinterOpt is a function type that allows customization of a Linter instance.
+type LinterOpt func(*Linter)
+
+// WithRules returns a LinterOpt that adds custom rules to the built-in ones.
+func WithRules(rules ...Rule) LinterOpt {
+	return func(l *Linter) {
+		l.rules = append(l.rules, rules...)
+	}
+}
+
+// WithSeverity returns a LinterOpt that overrides the severity of the named rule.
+// SeverityOff disables the rule.
+func WithSeverity(rule string, severity Severity) LinterOpt {
+	return func(l *Linter) {
+		l.severities[rule] = severity
+	}
+}
+
+// NewLinter creates a new Linter with the built-in rules.
+func NewLinter(opts ...LinterOpt) *Linter {
+	l := &Linter{
+		rules:      DefaultRules(),
+		severities: make(map[string]Severity),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Rules returns the linter rules with their effective severities.
+func (l *Linter) Rules() []Rule {
+	rules := make([]Rule, len(l.rules))
+
+	for i, rule := range l.rules {
+		if severity, ok := l.severities[rule.Name]; ok {
+			rule.Severity = severity
+		}
+
+		rules[i] = rule
+	}
+
+	return rules
+}
+
+// Lint checks the schema against enabled rules and returns findings
+// ordered by table, column and rule.
+func (l *Linter) Lint(s dberd.Schema) []Finding {
+	var findings []Finding
+
+	for _, rule := range l.Rules() {
+		if rule.Severity == SeverityOff {
+			continue
+		}
+
+		for _, finding := range rule.Check(s) {
+			finding.Rule = rule.Name
+			finding.Severity = rule.Severity
+			findings = append(findings, finding)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		switch {
+		case findings[i].Table != findings[j].Table:
+			return findings[i].Table < findings[j].Table
+		case findings[i].Column != findings[j].Column:
+			return findings[i].Column < findings[j].Column
+		default:
+			return findings[i].Rule < findings[j].Rule
+		}
+	})
+
+	return findings
+}
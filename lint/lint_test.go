@@ -0,0 +1,181 @@
+package lint
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/holydocs/dberd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lintTestSchema() dberd.Schema {
+	return dberd.Schema{
+		Tables: []dberd.Table{
+			{
+				Name: "public.users",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "int8 NOT NULL", IsPrimary: true, NotNull: true, Comment: "User ID"},
+					{Name: "email", Definition: "text NOT NULL", NotNull: true, Comment: "Login email"},
+					{Name: "createdAt", Definition: "timestamp DEFAULT now()", Comment: "Creation time"},
+				},
+				Indexes: []dberd.Index{
+					{Name: "users_pkey", Columns: []string{"id"}, IsPrimary: true},
+				},
+			},
+			{
+				Name: "public.posts",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "int8 NOT NULL", IsPrimary: true, NotNull: true},
+					{Name: "user_id", Definition: "int4", Comment: "Author"},
+					{Name: "editor_id", Definition: "int8 NOT NULL", NotNull: true, Comment: "Last editor"},
+				},
+				Indexes: []dberd.Index{
+					{Name: "posts_pkey", Columns: []string{"id"}, IsPrimary: true},
+					{Name: "posts_editor_id_idx", Columns: []string{"editor_id", "id"}},
+				},
+			},
+			{
+				Name: "public.post_views",
+				Columns: []dberd.Column{
+					// Nullability is parsed from the definition for sources not setting NotNull.
+					{Name: "post_id", Definition: "int8 NOT NULL", Comment: "Viewed post"},
+					{Name: "viewed_at", Definition: "timestamp", Comment: "View time"},
+				},
+			},
+			{
+				Name:          "public.remote_users",
+				ForeignServer: "legacy",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "int8", Comment: "User ID"},
+				},
+			},
+		},
+		References: []dberd.Reference{
+			{
+				Source: dberd.TableColumn{Table: "public.posts", Column: "user_id"},
+				Target: dberd.TableColumn{Table: "public.users", Column: "id"},
+			},
+			{
+				Source: dberd.TableColumn{Table: "public.posts", Column: "editor_id"},
+				Target: dberd.TableColumn{Table: "public.users", Column: "id"},
+			},
+			{
+				Source: dberd.TableColumn{Table: "public.post_views", Column: "post_id"},
+				Target: dberd.TableColumn{Table: "public.posts", Column: "id"},
+			},
+		},
+	}
+}
+
+func TestLinter_Lint(t *testing.T) {
+	t.Parallel()
+
+	findings := NewLinter().Lint(lintTestSchema())
+
+	expected := []Finding{
+		{
+			Rule: RulePrimaryKey, Severity: SeverityError, Table: "public.post_views",
+			Message: "table has no primary key",
+		},
+		{
+			Rule: RuleFKIndex, Severity: SeverityWarning, Table: "public.post_views", Column: "post_id",
+			Message: "foreign key to public.posts.id has no supporting index",
+		},
+		{
+			Rule: RuleColumnComment, Severity: SeverityInfo, Table: "public.posts",
+			Message: "columns without comments: id",
+		},
+		{
+			Rule: RuleFKIndex, Severity: SeverityWarning, Table: "public.posts", Column: "user_id",
+			Message: "foreign key to public.users.id has no supporting index",
+		},
+		{
+			Rule: RuleFKType, Severity: SeverityError, Table: "public.posts", Column: "user_id",
+			Message: "foreign key type INT4 differs from public.users.id type INT8",
+		},
+		{
+			Rule: RuleNullableFK, Severity: SeverityInfo, Table: "public.posts", Column: "user_id",
+			Message: "foreign key to public.users.id is nullable",
+		},
+		{
+			Rule: RuleNaming, Severity: SeverityWarning, Table: "public.users", Column: "createdAt",
+			Message: "column name createdAt is not snake_case like most names",
+		},
+	}
+
+	assert.Equal(t, expected, findings)
+
+	t.Run("overrides severities", func(t *testing.T) {
+		t.Parallel()
+
+		custom := Rule{
+			Name:     "no-views",
+			Severity: SeverityWarning,
+			Check: func(s dberd.Schema) []Finding {
+				return []Finding{{Table: s.Tables[2].Name, Message: "views table"}}
+			},
+		}
+
+		linter := NewLinter(
+			WithRules(custom),
+			WithSeverity(RuleFKIndex, SeverityOff),
+			WithSeverity(RuleColumnComment, SeverityOff),
+			WithSeverity(RuleNullableFK, SeverityOff),
+			WithSeverity(RuleNaming, SeverityOff),
+			WithSeverity(RuleFKType, SeverityOff),
+			WithSeverity(RulePrimaryKey, SeverityWarning),
+		)
+
+		expected := []Finding{
+			{Rule: "no-views", Severity: SeverityWarning, Table: "public.post_views", Message: "views table"},
+			{Rule: RulePrimaryKey, Severity: SeverityWarning, Table: "public.post_views", Message: "table has no primary key"},
+		}
+
+		assert.Equal(t, expected, linter.Lint(lintTestSchema()))
+	})
+}
+
+func TestColumnType(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"int8 NOT NULL DEFAULT 1":              "INT8",
+		"character varying(255) COLLATE \"C\"": "CHARACTER VARYING(255)",
+		"bigint unsigned AUTO_INCREMENT":       "BIGINT UNSIGNED",
+		"Nullable(UInt64)":                     "UINT64",
+		"LowCardinality(String) CODEC(ZSTD)":   "STRING",
+		"timestamp with time zone":             "TIMESTAMP WITH TIME ZONE",
+	}
+
+	for definition, expected := range tests {
+		assert.Equal(t, expected, columnType(dberd.Column{Definition: definition}), definition)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	t.Parallel()
+
+	linter := NewLinter()
+	findings := linter.Lint(lintTestSchema())
+
+	for _, format := range []Format{FormatText, FormatJSON, FormatSARIF} {
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			err := WriteReport(&buf, format, linter.Rules(), findings)
+			require.NoError(t, err)
+
+			expected, err := os.ReadFile("testdata/report." + string(format))
+			require.NoError(t, err)
+
+			assert.Equal(t, string(expected), buf.String())
+		})
+	}
+
+	err := WriteReport(&bytes.Buffer{}, "xml", nil, nil)
+	require.EqualError(t, err, `unknown report format "xml"`)
+}
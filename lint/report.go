@@ -0,0 +1,207 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/holydocs/dberd"
+)
+
+// Format represents a lint report format.
+type Format string
+
+const (
+	// FormatText is a human-readable report, one finding per line.
+	FormatText = Format("text")
+	// FormatJSON is a JSON array of findings.
+	FormatJSON = Format("json")
+	// FormatSARIF is a SARIF 2.1.0 log, understood by code scanning tools.
+	FormatSARIF = Format("sarif")
+)
+
+// ParseFormat parses a report format name.
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(s); format {
+	case FormatText, FormatJSON, FormatSARIF:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown report format %q", s)
+	}
+}
+
+// WriteReport writes findings of the given rules to w in the given format.
+func WriteReport(w io.Writer, format Format, rules []Rule, findings []Finding) error {
+	switch format {
+	case FormatText:
+		return WriteText(w, findings)
+	case FormatJSON:
+		return WriteJSON(w, findings)
+	case FormatSARIF:
+		return WriteSARIF(w, rules, findings)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+// WriteText writes findings as text lines, e.g. "error: public.users: table has no primary key (primary-key)",
+// followed by a summary line.
+func WriteText(w io.Writer, findings []Finding) error {
+	for _, f := range findings {
+		_, err := fmt.Fprintf(w, "%s: %s: %s (%s)\n", f.Severity, f.Location(), f.Message, f.Rule)
+		if err != nil {
+			return fmt.Errorf("writing finding: %w", err)
+		}
+	}
+
+	counts := make(map[Severity]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+
+	_, err := fmt.Fprintf(w, "%d findings: %d errors, %d warnings, %d info\n",
+		len(findings), counts[SeverityError], counts[SeverityWarning], counts[SeverityInfo])
+	if err != nil {
+		return fmt.Errorf("writing summary: %w", err)
+	}
+
+	return nil
+}
+
+// WriteJSON writes findings as a JSON array.
+func WriteJSON(w io.Writer, findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(findings); err != nil {
+		return fmt.Errorf("encoding findings: %w", err)
+	}
+
+	return nil
+}
+
+// sarifLog is the subset of the SARIF 2.1.0 log format used for lint reports.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Enabled bool   `json:"enabled"`
+	Level   string `json:"level"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIF writes findings as a SARIF 2.1.0 log. Schema objects have no source files,
+// so findings are located with logical locations named after their table or column.
+func WriteSARIF(w io.Writer, rules []Rule, findings []Finding) error {
+	driver := sarifDriver{
+		Name:           "dberd",
+		Version:        dberd.Version,
+		InformationURI: "https://github.com/holydocs/dberd",
+		Rules:          make([]sarifRule, len(rules)),
+	}
+
+	for i, rule := range rules {
+		driver.Rules[i] = sarifRule{
+			ID:               rule.Name,
+			ShortDescription: sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{
+				Enabled: rule.Severity != SeverityOff,
+				Level:   sarifLevel(rule.Severity),
+			},
+		}
+	}
+
+	results := make([]sarifResult, len(findings))
+
+	for i, f := range findings {
+		kind := "table"
+		if f.Column != "" {
+			kind = "column"
+		}
+
+		results[i] = sarifResult{
+			RuleID:  f.Rule,
+			Level:   sarifLevel(f.Severity),
+			Message: sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: f.Location(), Kind: kind}},
+			}},
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	err := enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+	if err != nil {
+		return fmt.Errorf("encoding SARIF log: %w", err)
+	}
+
+	return nil
+}
+
+// sarifLevel maps severities to SARIF result levels.
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "note"
+	default:
+		return "none"
+	}
+}
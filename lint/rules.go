@@ -0,0 +1,334 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/holydocs/dberd"
+)
+
+// Built-in rule names.
+const (
+	RulePrimaryKey    = "primary-key"
+	RuleFKIndex       = "fk-index"
+	RuleFKType        = "fk-type"
+	RuleNullableFK    = "nullable-fk"
+	RuleNaming        = "naming"
+	RuleColumnComment = "column-comment"
+)
+
+// DefaultRules returns the built-in rules with their default severities.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Name:        RulePrimaryKey,
+			Description: "Tables should have a primary key",
+			Severity:    SeverityError,
+			Check:       checkPrimaryKey,
+		},
+		{
+			Name:        RuleFKIndex,
+			Description: "Foreign key columns should be the leading column of an index",
+			Severity:    SeverityWarning,
+			Check:       checkFKIndex,
+		},
+		{
+			Name:        RuleFKType,
+			Description: "Foreign key columns should have the same type as the referenced columns",
+			Severity:    SeverityError,
+			Check:       checkFKType,
+		},
+		{
+			Name:        RuleNullableFK,
+			Description: "Foreign key columns should be NOT NULL",
+			Severity:    SeverityInfo,
+			Check:       checkNullableFK,
+		},
+		{
+			Name:        RuleNaming,
+			Description: "Table and column names should follow the naming convention used by most names",
+			Severity:    SeverityWarning,
+			Check:       checkNaming,
+		},
+		{
+			Name:        RuleColumnComment,
+			Description: "Columns should have comments",
+			Severity:    SeverityInfo,
+			Check:       checkColumnComments,
+		},
+	}
+}
+
+// checkPrimaryKey reports tables without a primary key. Foreign tables are skipped,
+// as they can't have one.
+func checkPrimaryKey(s dberd.Schema) []Finding {
+	var findings []Finding
+
+	for _, table := range s.Tables {
+		if table.ForeignServer != "" || hasPrimaryKey(table) {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Table:   table.Name,
+			Message: "table has no primary key",
+		})
+	}
+
+	return findings
+}
+
+func hasPrimaryKey(table dberd.Table) bool {
+	for _, column := range table.Columns {
+		if column.IsPrimary {
+			return true
+		}
+	}
+
+	for _, index := range table.Indexes {
+		if index.IsPrimary {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkFKIndex reports foreign key columns which are not the leading column of any index.
+// The rule is skipped for schemas without indexes, as their source doesn't extract them.
+func checkFKIndex(s dberd.Schema) []Finding {
+	tables := tablesByName(s)
+
+	var hasIndexes bool
+
+	for _, table := range s.Tables {
+		if len(table.Indexes) > 0 {
+			hasIndexes = true
+			break
+		}
+	}
+
+	if !hasIndexes {
+		return nil
+	}
+
+	var findings []Finding
+
+	for _, ref := range foreignKeys(s) {
+		table, ok := tables[ref.Source.Table]
+		if !ok || hasLeadingIndex(table, ref.Source.Column) {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Table:   ref.Source.Table,
+			Column:  ref.Source.Column,
+			Message: fmt.Sprintf("foreign key to %s.%s has no supporting index", ref.Target.Table, ref.Target.Column),
+		})
+	}
+
+	return findings
+}
+
+func hasLeadingIndex(table *dberd.Table, column string) bool {
+	for _, index := range table.Indexes {
+		if len(index.Columns) > 0 && index.Columns[0] == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkFKType reports foreign key columns which type differs from the referenced column type.
+func checkFKType(s dberd.Schema) []Finding {
+	tables := tablesByName(s)
+
+	var findings []Finding
+
+	for _, ref := range foreignKeys(s) {
+		source, ok := column(tables, ref.Source)
+		if !ok {
+			continue
+		}
+
+		target, ok := column(tables, ref.Target)
+		if !ok {
+			continue
+		}
+
+		sourceType, targetType := columnType(source), columnType(target)
+		if sourceType == targetType {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Table:  ref.Source.Table,
+			Column: ref.Source.Column,
+			Message: fmt.Sprintf("foreign key type %s differs from %s.%s type %s",
+				sourceType, ref.Target.Table, ref.Target.Column, targetType),
+		})
+	}
+
+	return findings
+}
+
+// checkNullableFK reports nullable foreign key columns.
+func checkNullableFK(s dberd.Schema) []Finding {
+	tables := tablesByName(s)
+
+	var findings []Finding
+
+	for _, ref := range foreignKeys(s) {
+		source, ok := column(tables, ref.Source)
+		if !ok || !source.Nullable() {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Table:   ref.Source.Table,
+			Column:  ref.Source.Column,
+			Message: fmt.Sprintf("foreign key to %s.%s is nullable", ref.Target.Table, ref.Target.Column),
+		})
+	}
+
+	return findings
+}
+
+var (
+	snakeCase = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)+$`)
+	camelCase = regexp.MustCompile(`^[a-z][a-z0-9]*([A-Z][a-z0-9]*)+$`)
+)
+
+// checkNaming reports table and column names which don't follow the naming convention
+// used by most names in the schema, either snake_case or camelCase.
+// Single lowercase words, e.g. "id", follow both conventions.
+func checkNaming(s dberd.Schema) []Finding {
+	type name struct {
+		table, column, name string
+	}
+
+	var names []name
+
+	for _, table := range s.Tables {
+		tableName := table.Name
+		if i := strings.LastIndex(tableName, "."); i >= 0 {
+			tableName = tableName[i+1:]
+		}
+
+		names = append(names, name{table: table.Name, name: tableName})
+
+		for _, column := range table.Columns {
+			names = append(names, name{table: table.Name, column: column.Name, name: column.Name})
+		}
+	}
+
+	var snake, camel int
+
+	for _, n := range names {
+		switch {
+		case snakeCase.MatchString(n.name):
+			snake++
+		case camelCase.MatchString(n.name):
+			camel++
+		}
+	}
+
+	convention, other := "snake_case", camelCase
+	if camel > snake {
+		convention, other = "camelCase", snakeCase
+	}
+
+	var findings []Finding
+
+	for _, n := range names {
+		if !other.MatchString(n.name) {
+			continue
+		}
+
+		kind := "table"
+		if n.column != "" {
+			kind = "column"
+		}
+
+		findings = append(findings, Finding{
+			Table:   n.table,
+			Column:  n.column,
+			Message: fmt.Sprintf("%s name %s is not %s like most names", kind, n.name, convention),
+		})
+	}
+
+	return findings
+}
+
+// checkColumnComments reports tables with columns without comments, one finding per table.
+func checkColumnComments(s dberd.Schema) []Finding {
+	var findings []Finding
+
+	for _, table := range s.Tables {
+		var missing []string
+
+		for _, column := range table.Columns {
+			if column.Comment == "" {
+				missing = append(missing, column.Name)
+			}
+		}
+
+		if len(missing) == 0 {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Table:   table.Name,
+			Message: "columns without comments: " + strings.Join(missing, ", "),
+		})
+	}
+
+	return findings
+}
+
+func tablesByName(s dberd.Schema) map[string]*dberd.Table {
+	tables := make(map[string]*dberd.Table, len(s.Tables))
+	for i := range s.Tables {
+		tables[s.Tables[i].Name] = &s.Tables[i]
+	}
+
+	return tables
+}
+
+// foreignKeys returns foreign key references, skipping inheritance and collapsed many-to-many ones.
+func foreignKeys(s dberd.Schema) []dberd.Reference {
+	var refs []dberd.Reference
+
+	for _, ref := range s.References {
+		if ref.Kind == dberd.ReferenceKindForeignKey && !ref.IsManyToMany() {
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
+
+func column(tables map[string]*dberd.Table, tc dberd.TableColumn) (dberd.Column, bool) {
+	table, ok := tables[tc.Table]
+	if !ok {
+		return dberd.Column{}, false
+	}
+
+	return table.Column(tc.Column)
+}
+
+// columnType returns the uppercase column type, e.g. "INT8" for "int8 NOT NULL DEFAULT 1".
+// ClickHouse Nullable and LowCardinality wrappers are removed, as they don't change the value type.
+func columnType(column dberd.Column) string {
+	definition := strings.ToUpper(strings.TrimSpace(column.Type()))
+
+	for _, wrapper := range []string{"LOWCARDINALITY(", "NULLABLE("} {
+		if strings.HasPrefix(definition, wrapper) && strings.HasSuffix(definition, ")") {
+			definition = definition[len(wrapper) : len(definition)-1]
+		}
+	}
+
+	return definition
+}
@@ -0,0 +1,284 @@
+package dberd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// OptionSpec describes an option accepted by a registered source or target factory.
+type OptionSpec struct {
+	Name        string
+	Description string
+	// Default is the value used when the option is not given, empty means unset.
+	Default string
+}
+
+// Options holds option values passed to registered factories, keyed by option name.
+type Options map[string]string
+
+// Bool returns the named option as a boolean. Missing options are false.
+func (o Options) Bool(name string) (bool, error) {
+	v, ok := o[name]
+	if !ok || v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("option %s: %w", name, err)
+	}
+
+	return b, nil
+}
+
+// SourceFactory creates a Source connected to the given DSN.
+type SourceFactory func(dsn string, opts Options) (Source, error)
+
+// TargetFactory creates a Target.
+type TargetFactory func(opts Options) (Target, error)
+
+// SourceRegistration describes a named source implementation.
+type SourceRegistration struct {
+	Name        string
+	Description string
+	Options     []OptionSpec
+	New         SourceFactory
+	// Schemes are DSN schemes served by the source, e.g. "postgres" for postgres://.
+	Schemes []string
+	// Probe optionally reports whether the database behind the DSN is served by the source.
+	// It is used to choose between sources sharing a scheme.
+	Probe func(ctx context.Context, dsn string) (bool, error)
+}
+
+// TargetRegistration describes a named target implementation.
+type TargetRegistration struct {
+	Name        string
+	Description string
+	Options     []OptionSpec
+	// SourceOptions are source options the target relies on, e.g. to extract optional
+	// schema sections. They are applied to sources that declare them.
+	SourceOptions Options
+	New           TargetFactory
+}
+
+var (
+	registryMu sync.RWMutex
+	sources    = make(map[string]SourceRegistration)
+	targets    = make(map[string]TargetRegistration)
+)
+
+// RegisterSource makes a source available by name. It is meant to be called from the init
+// function of the package implementing the source, and panics if the name is empty,
+// the factory is nil or the name is already registered.
+func RegisterSource(r SourceRegistration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if r.Name == "" || r.New == nil {
+		panic("dberd: RegisterSource requires a name and a factory")
+	}
+
+	if _, dup := sources[r.Name]; dup {
+		panic("dberd: RegisterSource called twice for source " + r.Name)
+	}
+
+	sources[r.Name] = r
+}
+
+// RegisterTarget makes a target available by name. It is meant to be called from the init
+// function of the package implementing the target, and panics if the name is empty,
+// the factory is nil or the name is already registered.
+func RegisterTarget(r TargetRegistration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if r.Name == "" || r.New == nil {
+		panic("dberd: RegisterTarget requires a name and a factory")
+	}
+
+	if _, dup := targets[r.Name]; dup {
+		panic("dberd: RegisterTarget called twice for target " + r.Name)
+	}
+
+	targets[r.Name] = r
+}
+
+// Sources returns registered sources sorted by name.
+func Sources() []SourceRegistration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	list := make([]SourceRegistration, 0, len(sources))
+	for _, r := range sources {
+		list = append(list, r)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+// Targets returns registered targets sorted by name.
+func Targets() []TargetRegistration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	list := make([]TargetRegistration, 0, len(targets))
+	for _, r := range targets {
+		list = append(list, r)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+// LookupSource returns the source registered under the given name.
+func LookupSource(name string) (SourceRegistration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	r, ok := sources[name]
+
+	return r, ok
+}
+
+// LookupTarget returns the target registered under the given name.
+func LookupTarget(name string) (TargetRegistration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	r, ok := targets[name]
+
+	return r, ok
+}
+
+// NewSource creates a registered source by name. Options not declared by the source are rejected.
+func NewSource(name, dsn string, opts Options) (Source, error) {
+	r, ok := LookupSource(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown source %q", name)
+	}
+
+	opts, err := resolveOptions(r.Options, opts)
+	if err != nil {
+		return nil, fmt.Errorf("source %s: %w", name, err)
+	}
+
+	return r.New(dsn, opts)
+}
+
+// Open creates a registered source serving the given DSN, see ResolveSource.
+func Open(ctx context.Context, dsn string, opts Options) (Source, error) {
+	r, err := ResolveSource(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSource(r.Name, dsn, opts)
+}
+
+// ResolveSource returns the registered source serving the given DSN by its scheme.
+// When several sources share the scheme, sources with a Probe are asked first
+// and a single source without one is used as a fallback.
+func ResolveSource(ctx context.Context, dsn string) (SourceRegistration, error) {
+	scheme, _, found := strings.Cut(dsn, ":")
+	if !found || scheme == "" {
+		return SourceRegistration{}, errors.New("no scheme in DSN")
+	}
+
+	scheme = strings.ToLower(scheme)
+
+	var candidates []SourceRegistration
+
+	for _, r := range Sources() {
+		if slices.Contains(r.Schemes, scheme) {
+			candidates = append(candidates, r)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return SourceRegistration{}, fmt.Errorf("no source registered for scheme %q", scheme)
+	case 1:
+		return candidates[0], nil
+	}
+
+	var fallbacks []SourceRegistration
+
+	for _, r := range candidates {
+		if r.Probe == nil {
+			fallbacks = append(fallbacks, r)
+			continue
+		}
+
+		ok, err := r.Probe(ctx, dsn)
+		if err != nil {
+			return SourceRegistration{}, fmt.Errorf("probing %s source: %w", r.Name, err)
+		}
+
+		if ok {
+			return r, nil
+		}
+	}
+
+	if len(fallbacks) != 1 {
+		return SourceRegistration{}, fmt.Errorf("ambiguous scheme %q, source has to be set explicitly", scheme)
+	}
+
+	return fallbacks[0], nil
+}
+
+// NewTarget creates a registered target by name. Options not declared by the target are rejected.
+func NewTarget(name string, opts Options) (Target, error) {
+	r, ok := LookupTarget(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown target %q", name)
+	}
+
+	opts, err := resolveOptions(r.Options, opts)
+	if err != nil {
+		return nil, fmt.Errorf("target %s: %w", name, err)
+	}
+
+	return r.New(opts)
+}
+
+// resolveOptions checks given options against specs and fills in defaults.
+func resolveOptions(specs []OptionSpec, given Options) (Options, error) {
+	known := make(map[string]OptionSpec, len(specs))
+	for _, spec := range specs {
+		known[spec.Name] = spec
+	}
+
+	for name := range given {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("unknown option %q", name)
+		}
+	}
+
+	opts := make(Options, len(specs))
+
+	for _, spec := range specs {
+		if v, ok := given[spec.Name]; ok {
+			opts[spec.Name] = v
+			continue
+		}
+
+		if spec.Default != "" {
+			opts[spec.Name] = spec.Default
+		}
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,160 @@
+package dberd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registryTestSource struct {
+	dsn  string
+	opts Options
+}
+
+func (s *registryTestSource) ExtractSchema(_ context.Context) (Schema, error) {
+	return Schema{}, nil
+}
+
+func (s *registryTestSource) Close() error {
+	return nil
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	RegisterSource(SourceRegistration{
+		Name:        "registry-test",
+		Description: "Registry test source",
+		Options: []OptionSpec{
+			{Name: "stats", Description: "Extract stats", Default: "false"},
+			{Name: "schema", Description: "Schema name"},
+		},
+		New: func(dsn string, opts Options) (Source, error) {
+			return &registryTestSource{dsn: dsn, opts: opts}, nil
+		},
+	})
+
+	t.Run("lists registered sources", func(t *testing.T) {
+		t.Parallel()
+
+		r, ok := LookupSource("registry-test")
+		require.True(t, ok)
+		assert.Equal(t, "Registry test source", r.Description)
+
+		var names []string
+		for _, source := range Sources() {
+			names = append(names, source.Name)
+		}
+
+		assert.Contains(t, names, "registry-test")
+	})
+
+	t.Run("fills in defaults", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := NewSource("registry-test", "dsn", Options{"schema": "app"})
+		require.NoError(t, err)
+
+		source, ok := s.(*registryTestSource)
+		require.True(t, ok)
+		assert.Equal(t, "dsn", source.dsn)
+		assert.Equal(t, Options{"stats": "false", "schema": "app"}, source.opts)
+	})
+
+	t.Run("rejects unknown options", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewSource("registry-test", "dsn", Options{"unknown": "true"})
+		require.EqualError(t, err, `source registry-test: unknown option "unknown"`)
+	})
+
+	t.Run("rejects unknown sources", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewSource("registry-missing", "dsn", nil)
+		require.EqualError(t, err, `unknown source "registry-missing"`)
+	})
+
+	t.Run("panics on duplicates", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() {
+			RegisterSource(SourceRegistration{
+				Name: "registry-test",
+				New: func(_ string, _ Options) (Source, error) {
+					return nil, nil
+				},
+			})
+		})
+	})
+}
+
+func TestResolveSource(t *testing.T) {
+	t.Parallel()
+
+	newSource := func(_ string, _ Options) (Source, error) {
+		return &registryTestSource{}, nil
+	}
+
+	RegisterSource(SourceRegistration{Name: "resolve-sql", New: newSource, Schemes: []string{"resolvesql"}})
+	RegisterSource(SourceRegistration{
+		Name:    "resolve-sql-fork",
+		New:     newSource,
+		Schemes: []string{"resolvesqlfork", "resolvesql"},
+		Probe: func(_ context.Context, dsn string) (bool, error) {
+			return strings.Contains(dsn, "fork"), nil
+		},
+	})
+
+	tests := []struct {
+		name     string
+		dsn      string
+		expected string
+		err      string
+	}{
+		{name: "own scheme", dsn: "resolvesqlfork://host/db", expected: "resolve-sql-fork"},
+		{name: "probed", dsn: "resolvesql://fork/db", expected: "resolve-sql-fork"},
+		{name: "fallback", dsn: "ResolveSQL://host/db", expected: "resolve-sql"},
+		{name: "unknown scheme", dsn: "resolvenosql://host/db", err: `no source registered for scheme "resolvenosql"`},
+		{name: "no scheme", dsn: "host/db", err: "no scheme in DSN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := ResolveSource(context.Background(), tt.dsn)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, r.Name)
+		})
+	}
+}
+
+func TestOptions_Bool(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{"on": "true", "off": "0", "bad": "maybe"}
+
+	on, err := opts.Bool("on")
+	require.NoError(t, err)
+	assert.True(t, on)
+
+	off, err := opts.Bool("off")
+	require.NoError(t, err)
+	assert.False(t, off)
+
+	missing, err := opts.Bool("missing")
+	require.NoError(t, err)
+	assert.False(t, missing)
+
+	_, err = opts.Bool("bad")
+	require.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"fmt"
 	"io"
+	"strings"
 
 	_ "github.com/ClickHouse/clickhouse-go/v2" // import clickhouse driver
 	"github.com/holydocs/dberd"
@@ -17,32 +18,89 @@ var (
 	_ dberd.Source = (*Source)(nil)
 )
 
+func init() {
+	dberd.RegisterSource(dberd.SourceRegistration{
+		Name:        "clickhouse",
+		Description: "ClickHouse database",
+		Options: []dberd.OptionSpec{
+			{Name: "stats", Description: "Extract row counts and on-disk sizes of MergeTree tables", Default: "false"},
+		},
+		New:     newRegisteredSource,
+		Schemes: []string{"clickhouse"},
+	})
+}
+
+// newRegisteredSource creates a Source from registry options.
+func newRegisteredSource(dsn string, opts dberd.Options) (dberd.Source, error) {
+	stats, err := opts.Bool("stats")
+	if err != nil {
+		return nil, err
+	}
+
+	var sourceOpts []SourceOpt
+	if stats {
+		sourceOpts = append(sourceOpts, WithStats())
+	}
+
+	s, err := NewSource(dsn, sourceOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
 // Source represents a ClickHouse database source for schema extraction.
 type Source struct {
 	db     *sql.DB
 	closer io.Closer
+
+	extractStats bool
+}
+
+// SourceOpt is a function type that allows customization of a Source instance.
+type SourceOpt func(*Source)
+
+// WithStats returns a SourceOpt that extracts row counts and on-disk sizes of MergeTree tables
+// from their active data parts.
+func WithStats() SourceOpt {
+	return func(s *Source) {
+		s.extractStats = true
+	}
 }
 
 // NewSource creates a new ClickHouse source from a connection string.
-func NewSource(connStr string) (*Source, error) {
+func NewSource(connStr string, opts ...SourceOpt) (*Source, error) {
 	db, err := sql.Open("clickhouse", connStr)
 	if err != nil {
 		return nil, fmt.Errorf("opening sql connection: %w", err)
 	}
 
-	return &Source{
+	s := &Source{
 		db:     db,
 		closer: db,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
 }
 
 // NewSourceFromDB creates a new ClickHouse source from an existing database connection.
 // This is useful when you want to reuse an existing database connection
 // for schema extraction purposes.
-func NewSourceFromDB(db *sql.DB) *Source {
-	return &Source{
+func NewSourceFromDB(db *sql.DB, opts ...SourceOpt) *Source {
+	s := &Source{
 		db: db,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Close closes the database connection if it was created by NewSource.
@@ -58,14 +116,38 @@ func (s *Source) Close() error {
 // ExtractSchema extracts the complete database schema including tables.
 // It returns a dberd.Schema containing all tables.
 func (s *Source) ExtractSchema(ctx context.Context) (schema dberd.Schema, err error) {
+	schema.Metadata, err = s.extractMetadata(ctx)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("extracting metadata: %w", err)
+	}
+
 	schema.Tables, err = s.extractTables(ctx)
 	if err != nil {
 		return dberd.Schema{}, fmt.Errorf("extracting tables: %w", err)
 	}
 
+	if s.extractStats {
+		err = s.extractTableStats(ctx, schema.Tables)
+		if err != nil {
+			return dberd.Schema{}, fmt.Errorf("extracting table stats: %w", err)
+		}
+	}
+
 	return schema, nil
 }
 
+const extractMetadataQuery = `SELECT currentDatabase(), version();`
+
+// extractMetadata queries the database for its name and server version.
+func (s *Source) extractMetadata(ctx context.Context) (*dberd.Metadata, error) {
+	var database, serverVersion string
+	if err := s.db.QueryRowContext(ctx, extractMetadataQuery).Scan(&database, &serverVersion); err != nil {
+		return nil, fmt.Errorf("querying metadata: %w", err)
+	}
+
+	return dberd.NewMetadata("clickhouse", serverVersion, database), nil
+}
+
 const extractTablesQuery = `
 	SELECT
 		database,
@@ -151,6 +233,7 @@ func tableRowsToSchemaTables(tableRows []tableRow) []dberd.Table {
 			Name:       row.columnName,
 			Definition: definition,
 			IsPrimary:  row.isPrimary,
+			NotNull:    !strings.Contains(row.dataType, "Nullable("),
 		}
 
 		if row.comment != nil {
@@ -168,3 +251,65 @@ func tableRowsToSchemaTables(tableRows []tableRow) []dberd.Table {
 
 	return tables
 }
+
+const extractTableStatsQuery = `
+	SELECT
+		database,
+		table,
+		toInt64(sum(rows)),
+		toInt64(sum(bytes_on_disk))
+	FROM system.parts
+	WHERE active
+	AND database NOT IN ('system', 'information_schema', 'INFORMATION_SCHEMA')
+	GROUP BY database, table
+	ORDER BY database, table;`
+
+type tableStatsRow struct {
+	database  string
+	tableName string
+	rows      int64
+	sizeBytes int64
+}
+
+// extractTableStats queries the database for table row counts and sizes
+// and sets them on the given tables.
+func (s *Source) extractTableStats(ctx context.Context, tables []dberd.Table) error {
+	rows, err := s.db.QueryContext(ctx, extractTableStatsQuery)
+	if err != nil {
+		return fmt.Errorf("querying table stats: %w", err)
+	}
+	defer rows.Close()
+
+	tableIndex := make(map[string]int, len(tables))
+	for i := range tables {
+		tableIndex[tables[i].Name] = i
+	}
+
+	for rows.Next() {
+		var r tableStatsRow
+		if err := rows.Scan(
+			&r.database,
+			&r.tableName,
+			&r.rows,
+			&r.sizeBytes,
+		); err != nil {
+			return fmt.Errorf("scanning table stats row: %w", err)
+		}
+
+		i, ok := tableIndex[r.database+"."+r.tableName]
+		if !ok {
+			continue
+		}
+
+		tables[i].Stats = &dberd.TableStats{
+			Rows:      r.rows,
+			SizeBytes: r.sizeBytes,
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("table stats rows error: %w", err)
+	}
+
+	return nil
+}
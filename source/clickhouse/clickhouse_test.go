@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"log/slog"
+	"slices"
 	"testing"
 	"time"
 
@@ -52,6 +53,7 @@ func TestExtractSchema(t *testing.T) {
 		) ENGINE = MergeTree();`,
 		`ALTER TABLE users COMMENT COLUMN email 'User email address';`,
 		`ALTER TABLE roles COMMENT COLUMN description 'Role description and permissions';`,
+		`INSERT INTO users (id, name, email) VALUES (1, 'John Doe', 'john@example.com');`,
 	}
 	for _, statement := range statements {
 		_, err := db.ExecContext(ctx, statement)
@@ -65,32 +67,41 @@ func TestExtractSchema(t *testing.T) {
 
 	actual.Sort()
 
+	require.NotNil(t, actual.Metadata)
+	assert.Equal(t, "clickhouse", actual.Metadata.Source)
+	assert.NotEmpty(t, actual.Metadata.ServerVersion)
+	assert.Equal(t, dberd.Version, actual.Metadata.Version)
+	assert.False(t, actual.Metadata.ExtractedAt.IsZero())
+
+	// Metadata depends on the server and extraction time, so it is checked separately.
+	actual.Metadata = nil
+
 	expected := dberd.Schema{
 		Tables: []dberd.Table{
 			{
 				Name: "clickhouse.users",
 				Columns: []dberd.Column{
-					{Name: "id", Definition: "UInt32", IsPrimary: true},
-					{Name: "name", Definition: "String"},
-					{Name: "email", Definition: "String", Comment: "User email address"},
-					{Name: "created_at", Definition: "DateTime DEFAULT now()"},
+					{Name: "id", Definition: "UInt32", IsPrimary: true, NotNull: true},
+					{Name: "name", Definition: "String", NotNull: true},
+					{Name: "email", Definition: "String", Comment: "User email address", NotNull: true},
+					{Name: "created_at", Definition: "DateTime DEFAULT now()", NotNull: true},
 				},
 			},
 			{
 				Name: "clickhouse.roles",
 				Columns: []dberd.Column{
-					{Name: "id", Definition: "UInt32", IsPrimary: true},
-					{Name: "name", Definition: "String"},
-					{Name: "description", Definition: "String", Comment: "Role description and permissions"},
-					{Name: "created_at", Definition: "DateTime DEFAULT now()"},
+					{Name: "id", Definition: "UInt32", IsPrimary: true, NotNull: true},
+					{Name: "name", Definition: "String", NotNull: true},
+					{Name: "description", Definition: "String", Comment: "Role description and permissions", NotNull: true},
+					{Name: "created_at", Definition: "DateTime DEFAULT now()", NotNull: true},
 				},
 			},
 			{
 				Name: "clickhouse.user_roles",
 				Columns: []dberd.Column{
-					{Name: "user_id", Definition: "UInt32", IsPrimary: true},
-					{Name: "role_id", Definition: "UInt32", IsPrimary: true},
-					{Name: "assigned_at", Definition: "DateTime DEFAULT now()"},
+					{Name: "user_id", Definition: "UInt32", IsPrimary: true, NotNull: true},
+					{Name: "role_id", Definition: "UInt32", IsPrimary: true, NotNull: true},
+					{Name: "assigned_at", Definition: "DateTime DEFAULT now()", NotNull: true},
 				},
 			},
 		},
@@ -99,6 +110,20 @@ func TestExtractSchema(t *testing.T) {
 	expected.Sort()
 
 	assert.Equal(t, expected, actual)
+
+	withStats, err := NewSourceFromDB(db, WithStats()).ExtractSchema(ctx)
+	require.NoError(t, err)
+
+	i := slices.IndexFunc(withStats.Tables, func(table dberd.Table) bool {
+		return table.Name == "clickhouse.users"
+	})
+	require.NotEqual(t, -1, i)
+
+	// Only tables with data parts have stats.
+	users := withStats.Tables[i]
+	require.NotNil(t, users.Stats)
+	assert.Equal(t, int64(1), users.Stats.Rows)
+	assert.Positive(t, users.Stats.SizeBytes)
 }
 
 func setupTestDB(t *testing.T) (testcontainers.Container, *sql.DB) {
@@ -7,6 +7,9 @@ import (
 	"database/sql"
 	"fmt"
 	"io"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/holydocs/dberd"
 	"github.com/jackc/pgx/v5"
@@ -18,18 +21,89 @@ var (
 	_ dberd.Source = (*Source)(nil)
 )
 
+func init() {
+	dberd.RegisterSource(dberd.SourceRegistration{
+		Name:        "cockroach",
+		Description: "CockroachDB database",
+		Options: []dberd.OptionSpec{
+			{Name: "stats", Description: "Extract estimated row counts of tables", Default: "false"},
+		},
+		New:     newRegisteredSource,
+		Schemes: []string{"cockroachdb", "postgres", "postgresql"},
+		Probe:   probe,
+	})
+}
+
+// newRegisteredSource creates a Source from registry options.
+func newRegisteredSource(dsn string, opts dberd.Options) (dberd.Source, error) {
+	stats, err := opts.Bool("stats")
+	if err != nil {
+		return nil, err
+	}
+
+	var sourceOpts []SourceOpt
+	if stats {
+		sourceOpts = append(sourceOpts, WithStats())
+	}
+
+	s, err := NewSource(dsn, sourceOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// probe reports whether the database behind a postgres DSN is CockroachDB,
+// which speaks the PostgreSQL wire protocol.
+func probe(ctx context.Context, dsn string) (bool, error) {
+	s, err := NewSource(dsn)
+	if err != nil {
+		return false, err
+	}
+
+	defer s.Close()
+
+	var version string
+
+	err = s.db.QueryRowContext(ctx, "SELECT version()").Scan(&version)
+	if err != nil {
+		return false, fmt.Errorf("querying version: %w", err)
+	}
+
+	return strings.Contains(version, "CockroachDB"), nil
+}
+
 // Source represents a CockroachDB database source for schema extraction.
 // It maintains a database connection and implements the dberd.SchemaExtractor interface
 // to provide schema information from a CockroachDB instance.
 type Source struct {
 	db     *sql.DB
 	closer io.Closer
+
+	extractStats bool
+}
+
+// SourceOpt is a function type that allows customization of a Source instance.
+type SourceOpt func(*Source)
+
+// WithStats returns a SourceOpt that extracts estimated row counts of tables.
+// On-disk sizes are not extracted, as they require scanning range statistics.
+func WithStats() SourceOpt {
+	return func(s *Source) {
+		s.extractStats = true
+	}
 }
 
 // NewSource creates a new CockroachDB source from a connection string.
 // It parses the connection string, establishes a database connection,
 // and returns a new Source instance ready for schema extraction.
-func NewSource(connStr string) (*Source, error) {
+// The cockroachdb:// scheme is accepted as an alias of postgres://.
+func NewSource(connStr string, opts ...SourceOpt) (*Source, error) {
+	if rest, ok := strings.CutPrefix(connStr, "cockroachdb://"); ok {
+		connStr = "postgres://" + rest
+	}
+
 	cockroachConfig, err := pgx.ParseConfig(connStr)
 	if err != nil {
 		return nil, fmt.Errorf("parsing cockroach connection string: %w", err)
@@ -38,19 +112,31 @@ func NewSource(connStr string) (*Source, error) {
 	cockroachConnector := stdlib.GetConnector(*cockroachConfig)
 	db := sql.OpenDB(cockroachConnector)
 
-	return &Source{
+	s := &Source{
 		db:     db,
 		closer: db,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
 }
 
 // NewSourceFromDB creates a new CockroachDB source from an existing database connection.
 // This is useful when you want to reuse an existing database connection
 // for schema extraction purposes.
-func NewSourceFromDB(db *sql.DB) *Source {
-	return &Source{
+func NewSourceFromDB(db *sql.DB, opts ...SourceOpt) *Source {
+	s := &Source{
 		db: db,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Close closes the database connection if it was created by NewSource.
@@ -66,19 +152,63 @@ func (s *Source) Close() error {
 // ExtractSchema extracts the complete database schema including tables and their references.
 // It returns a dberd.Schema containing all tables and their relationships.
 func (s *Source) ExtractSchema(ctx context.Context) (schema dberd.Schema, err error) {
+	schema.Metadata, err = s.extractMetadata(ctx)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("extracting metadata: %w", err)
+	}
+
 	schema.Tables, err = s.extractTables(ctx)
 	if err != nil {
 		return dberd.Schema{}, fmt.Errorf("extracting tables: %w", err)
 	}
 
+	err = s.extractLocalities(ctx, schema.Tables)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("extracting localities: %w", err)
+	}
+
+	err = s.extractIndexes(ctx, schema.Tables)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("extracting indexes: %w", err)
+	}
+
+	if s.extractStats {
+		err = s.extractTableStats(ctx, schema.Tables)
+		if err != nil {
+			return dberd.Schema{}, fmt.Errorf("extracting table stats: %w", err)
+		}
+	}
+
+	schema.Regions, err = s.extractRegions(ctx)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("extracting regions: %w", err)
+	}
+
 	schema.References, err = s.extractReferences(ctx)
 	if err != nil {
 		return dberd.Schema{}, fmt.Errorf("extracting references: %w", err)
 	}
 
+	schema.ComputeCardinality()
+
 	return schema, nil
 }
 
+const extractMetadataQuery = `
+	SELECT current_database(), value
+	FROM crdb_internal.node_build_info
+	WHERE field = 'Version';`
+
+// extractMetadata queries the database for its name and server version.
+func (s *Source) extractMetadata(ctx context.Context) (*dberd.Metadata, error) {
+	var database, serverVersion string
+	if err := s.db.QueryRowContext(ctx, extractMetadataQuery).Scan(&database, &serverVersion); err != nil {
+		return nil, fmt.Errorf("querying metadata: %w", err)
+	}
+
+	return dberd.NewMetadata("cockroach", serverVersion, database), nil
+}
+
 const extractTablesQuery = `
 	WITH pk_columns AS (
     	SELECT 
@@ -191,6 +321,7 @@ func tableRowsToSchemaTables(tableRows []tableRow) []dberd.Table {
 			Name:       row.columnName,
 			Definition: definition,
 			IsPrimary:  row.isPrimary,
+			NotNull:    row.isNullable == "NO",
 		}
 
 		if row.columnComment != nil {
@@ -209,6 +340,295 @@ func tableRowsToSchemaTables(tableRows []tableRow) []dberd.Table {
 	return tables
 }
 
+const extractLocalitiesQuery = `
+	SELECT
+		schema_name,
+		descriptor_name,
+		create_statement
+	FROM crdb_internal.create_statements
+	WHERE database_name = current_database()
+	AND descriptor_type = 'table'
+	AND (create_statement LIKE '%LOCALITY%' OR create_statement LIKE '%USING HASH%');`
+
+var (
+	localityRegexp         = regexp.MustCompile(`(?m)\bLOCALITY\s+(.+?)\s*;?$`)
+	hashShardedPKRegexp    = regexp.MustCompile(`(?i)PRIMARY KEY\s*\([^)]*\)\s*USING HASH`)
+	shardBucketCountRegexp = regexp.MustCompile(`(?i)PRIMARY KEY\s*\([^)]*\)\s*USING HASH[^,\n]*?bucket_count\s*=\s*(\d+)`)
+)
+
+// defaultShardBucketCount is a bucket count used by CockroachDB for hash-sharded indexes
+// when it is omitted in the create statement.
+const defaultShardBucketCount = 16
+
+type localityRow struct {
+	tableSchema     string
+	tableName       string
+	createStatement string
+}
+
+// extractLocalities queries the database for table create statements and sets multi-region
+// locality and hash-sharded primary key metadata on the given tables.
+func (s *Source) extractLocalities(ctx context.Context, tables []dberd.Table) error {
+	rows, err := s.db.QueryContext(ctx, extractLocalitiesQuery)
+	if err != nil {
+		return fmt.Errorf("querying localities: %w", err)
+	}
+	defer rows.Close()
+
+	var localityRows []localityRow
+
+	for rows.Next() {
+		var r localityRow
+		if err := rows.Scan(
+			&r.tableSchema,
+			&r.tableName,
+			&r.createStatement,
+		); err != nil {
+			return fmt.Errorf("scanning localities row: %w", err)
+		}
+
+		localityRows = append(localityRows, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("localities rows error: %w", err)
+	}
+
+	applyLocalityRows(tables, localityRows)
+
+	return nil
+}
+
+// applyLocalityRows parses create statements from a slice of localityRow and sets
+// the locality and primary key shard buckets on the matching tables.
+func applyLocalityRows(tables []dberd.Table, localityRows []localityRow) {
+	tableIndex := make(map[string]int, len(tables))
+	for i := range tables {
+		tableIndex[tables[i].Name] = i
+	}
+
+	for _, row := range localityRows {
+		i, ok := tableIndex[row.tableSchema+"."+row.tableName]
+		if !ok {
+			continue
+		}
+
+		if m := localityRegexp.FindStringSubmatch(row.createStatement); m != nil {
+			tables[i].Locality = m[1]
+		}
+
+		if hashShardedPKRegexp.MatchString(row.createStatement) {
+			tables[i].PrimaryKeyShardBuckets = defaultShardBucketCount
+
+			if m := shardBucketCountRegexp.FindStringSubmatch(row.createStatement); m != nil {
+				if n, err := strconv.Atoi(m[1]); err == nil {
+					tables[i].PrimaryKeyShardBuckets = n
+				}
+			}
+		}
+	}
+}
+
+// extractIndexesQuery lists key columns of every index, STORING and implicit columns
+// (such as hash-sharded key shard columns) are skipped.
+const extractIndexesQuery = `
+	SELECT
+		s.table_schema,
+		s.table_name,
+		s.index_name,
+		s.column_name,
+		s.non_unique = 'NO' AS is_unique,
+		EXISTS (
+			SELECT 1
+			FROM information_schema.table_constraints tc
+			WHERE tc.table_schema = s.table_schema
+			AND tc.table_name = s.table_name
+			AND tc.constraint_name = s.index_name
+			AND tc.constraint_type = 'PRIMARY KEY'
+		) AS is_primary
+	FROM information_schema.statistics s
+	WHERE s.storing = 'NO'
+	AND s.implicit = 'NO'
+	AND s.table_schema IN (SELECT schema_name FROM information_schema.schemata WHERE crdb_is_user_defined = 'YES')
+	ORDER BY s.table_schema, s.table_name, s.index_name, s.seq_in_index;`
+
+type indexRow struct {
+	tableSchema string
+	tableName   string
+	indexName   string
+	columnName  string
+	isUnique    bool
+	isPrimary   bool
+}
+
+// extractIndexes queries the database for table indexes and sets them on the given tables.
+func (s *Source) extractIndexes(ctx context.Context, tables []dberd.Table) error {
+	rows, err := s.db.QueryContext(ctx, extractIndexesQuery)
+	if err != nil {
+		return fmt.Errorf("querying indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexRows []indexRow
+
+	for rows.Next() {
+		var r indexRow
+		if err := rows.Scan(
+			&r.tableSchema,
+			&r.tableName,
+			&r.indexName,
+			&r.columnName,
+			&r.isUnique,
+			&r.isPrimary,
+		); err != nil {
+			return fmt.Errorf("scanning indexes row: %w", err)
+		}
+
+		indexRows = append(indexRows, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("indexes rows error: %w", err)
+	}
+
+	applyIndexRows(tables, indexRows)
+
+	return nil
+}
+
+// applyIndexRows groups a slice of indexRow by table and index and sets the resulting
+// dberd.Index values on the matching tables. Rows must be ordered by column position.
+func applyIndexRows(tables []dberd.Table, indexRows []indexRow) {
+	tableIndex := make(map[string]int, len(tables))
+	for i := range tables {
+		tableIndex[tables[i].Name] = i
+	}
+
+	indexPositions := make(map[string]int, len(indexRows))
+
+	for _, row := range indexRows {
+		tableKey := row.tableSchema + "." + row.tableName
+
+		i, ok := tableIndex[tableKey]
+		if !ok {
+			continue
+		}
+
+		indexKey := tableKey + "." + row.indexName
+
+		pos, exists := indexPositions[indexKey]
+		if !exists {
+			tables[i].Indexes = append(tables[i].Indexes, dberd.Index{
+				Name:      row.indexName,
+				IsUnique:  row.isUnique,
+				IsPrimary: row.isPrimary,
+			})
+			pos = len(tables[i].Indexes) - 1
+			indexPositions[indexKey] = pos
+		}
+
+		tables[i].Indexes[pos].Columns = append(tables[i].Indexes[pos].Columns, row.columnName)
+	}
+}
+
+const extractTableStatsQuery = `
+	SELECT
+		t.schema_name,
+		t.name,
+		COALESCE(s.estimated_row_count, 0)
+	FROM crdb_internal.tables t
+	LEFT JOIN crdb_internal.table_row_statistics s ON s.table_id = t.table_id
+	WHERE t.database_name = current_database()
+	AND t.drop_time IS NULL;`
+
+type tableStatsRow struct {
+	tableSchema string
+	tableName   string
+	rows        int64
+}
+
+// extractTableStats queries the database for estimated table row counts
+// and sets them on the given tables.
+func (s *Source) extractTableStats(ctx context.Context, tables []dberd.Table) error {
+	rows, err := s.db.QueryContext(ctx, extractTableStatsQuery)
+	if err != nil {
+		return fmt.Errorf("querying table stats: %w", err)
+	}
+	defer rows.Close()
+
+	tableIndex := make(map[string]int, len(tables))
+	for i := range tables {
+		tableIndex[tables[i].Name] = i
+	}
+
+	for rows.Next() {
+		var r tableStatsRow
+		if err := rows.Scan(
+			&r.tableSchema,
+			&r.tableName,
+			&r.rows,
+		); err != nil {
+			return fmt.Errorf("scanning table stats row: %w", err)
+		}
+
+		i, ok := tableIndex[r.tableSchema+"."+r.tableName]
+		if !ok {
+			continue
+		}
+
+		tables[i].Stats = &dberd.TableStats{
+			Rows: r.rows,
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("table stats rows error: %w", err)
+	}
+
+	return nil
+}
+
+const extractRegionsQuery = `
+	SELECT
+		primary_region,
+		array_to_string(regions, ','),
+		survival_goal
+	FROM crdb_internal.databases
+	WHERE name = current_database();`
+
+// extractRegions queries the database for its multi-region configuration.
+// It returns nil if the database is not multi-region.
+func (s *Source) extractRegions(ctx context.Context) (*dberd.DatabaseRegions, error) {
+	var (
+		primaryRegion *string
+		regions       *string
+		survivalGoal  *string
+	)
+
+	err := s.db.QueryRowContext(ctx, extractRegionsQuery).Scan(&primaryRegion, &regions, &survivalGoal)
+	if err != nil {
+		return nil, fmt.Errorf("querying regions: %w", err)
+	}
+
+	if primaryRegion == nil || *primaryRegion == "" {
+		return nil, nil
+	}
+
+	databaseRegions := &dberd.DatabaseRegions{
+		Primary: *primaryRegion,
+	}
+
+	if regions != nil && *regions != "" {
+		databaseRegions.Regions = strings.Split(*regions, ",")
+	}
+
+	if survivalGoal != nil {
+		databaseRegions.SurvivalGoal = *survivalGoal
+	}
+
+	return databaseRegions, nil
+}
+
 const extractReferencesQuery = `
 	WITH foreign_keys AS (
 		SELECT
@@ -102,87 +102,176 @@ func TestExtractSchema(t *testing.T) {
 
 	actual.Sort()
 
+	require.NotNil(t, actual.Metadata)
+	assert.Equal(t, "cockroach", actual.Metadata.Source)
+	assert.NotEmpty(t, actual.Metadata.ServerVersion)
+	assert.Equal(t, dberd.Version, actual.Metadata.Version)
+	assert.False(t, actual.Metadata.ExtractedAt.IsZero())
+
+	// Metadata depends on the server and extraction time, so it is checked separately.
+	actual.Metadata = nil
+
 	expected := dberd.Schema{
 		Tables: []dberd.Table{
 			{
 				Name: "public.users",
 				Columns: []dberd.Column{
-					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
-					{Name: "name", Definition: "VARCHAR(255) NOT NULL"},
-					{Name: "email", Definition: "VARCHAR(255) NOT NULL", Comment: "User email address"},
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true, NotNull: true},
+					{Name: "name", Definition: "VARCHAR(255) NOT NULL", NotNull: true},
+					{Name: "email", Definition: "VARCHAR(255) NOT NULL", Comment: "User email address", NotNull: true},
 					{Name: "created_at", Definition: "TIMESTAMP DEFAULT current_timestamp()"},
 				},
+				Indexes: []dberd.Index{
+					{Name: "users_pkey", Columns: []string{"id"}, IsUnique: true, IsPrimary: true},
+				},
 			},
 			{
 				Name: "public.roles",
 				Columns: []dberd.Column{
-					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
-					{Name: "name", Definition: "VARCHAR(50) NOT NULL"},
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true, NotNull: true},
+					{Name: "name", Definition: "VARCHAR(50) NOT NULL", NotNull: true},
 					{Name: "description", Definition: "STRING", Comment: "Role description and permissions"},
 					{Name: "created_at", Definition: "TIMESTAMP DEFAULT current_timestamp()"},
 				},
+				Indexes: []dberd.Index{
+					{Name: "roles_pkey", Columns: []string{"id"}, IsUnique: true, IsPrimary: true},
+				},
 			},
 			{
 				Name: "public.user_roles",
 				Columns: []dberd.Column{
-					{Name: "user_id", Definition: "INT8 NOT NULL", IsPrimary: true},
-					{Name: "role_id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "user_id", Definition: "INT8 NOT NULL", IsPrimary: true, NotNull: true},
+					{Name: "role_id", Definition: "INT8 NOT NULL", IsPrimary: true, NotNull: true},
 					{Name: "assigned_at", Definition: "TIMESTAMP DEFAULT current_timestamp()"},
 				},
+				Indexes: []dberd.Index{
+					{Name: "user_roles_pkey", Columns: []string{"user_id", "role_id"}, IsUnique: true, IsPrimary: true},
+				},
 			},
 			{
 				Name: "public.posts",
 				Columns: []dberd.Column{
-					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
-					{Name: "user_id", Definition: "INT8 NOT NULL"},
-					{Name: "title", Definition: "VARCHAR(255) NOT NULL"},
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true, NotNull: true},
+					{Name: "user_id", Definition: "INT8 NOT NULL", NotNull: true},
+					{Name: "title", Definition: "VARCHAR(255) NOT NULL", NotNull: true},
 					{Name: "content", Definition: "STRING"},
 					{Name: "created_at", Definition: "TIMESTAMP DEFAULT current_timestamp()"},
 				},
+				Indexes: []dberd.Index{
+					{Name: "posts_pkey", Columns: []string{"id"}, IsUnique: true, IsPrimary: true},
+				},
 			},
 			{
 				Name: "public.categories",
 				Columns: []dberd.Column{
-					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
-					{Name: "name", Definition: "VARCHAR(100) NOT NULL"},
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true, NotNull: true},
+					{Name: "name", Definition: "VARCHAR(100) NOT NULL", NotNull: true},
 					{Name: "description", Definition: "STRING"},
 					{Name: "parent_id", Definition: "INT8", Comment: "Self-referencing foreign key for category hierarchy"},
 					{Name: "created_at", Definition: "TIMESTAMP DEFAULT current_timestamp()"},
 				},
+				Indexes: []dberd.Index{
+					{Name: "categories_pkey", Columns: []string{"id"}, IsUnique: true, IsPrimary: true},
+				},
 			},
 			{
 				Name: "public.post_categories",
 				Columns: []dberd.Column{
-					{Name: "post_id", Definition: "INT8 NOT NULL", IsPrimary: true},
-					{Name: "category_id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "post_id", Definition: "INT8 NOT NULL", IsPrimary: true, NotNull: true},
+					{Name: "category_id", Definition: "INT8 NOT NULL", IsPrimary: true, NotNull: true},
+				},
+				Indexes: []dberd.Index{
+					{Name: "post_categories_pkey", Columns: []string{"post_id", "category_id"}, IsUnique: true, IsPrimary: true},
 				},
 			},
 			{
 				Name: "public.comments",
 				Columns: []dberd.Column{
-					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
-					{Name: "post_id", Definition: "INT8 NOT NULL"},
-					{Name: "user_id", Definition: "INT8 NOT NULL"},
-					{Name: "content", Definition: "STRING NOT NULL"},
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true, NotNull: true},
+					{Name: "post_id", Definition: "INT8 NOT NULL", NotNull: true},
+					{Name: "user_id", Definition: "INT8 NOT NULL", NotNull: true},
+					{Name: "content", Definition: "STRING NOT NULL", NotNull: true},
 					{Name: "created_at", Definition: "TIMESTAMP DEFAULT current_timestamp()"},
 				},
+				Indexes: []dberd.Index{
+					{Name: "comments_pkey", Columns: []string{"id"}, IsUnique: true, IsPrimary: true},
+				},
 			},
 		},
 		References: []dberd.Reference{
-			{Source: dberd.TableColumn{Table: "public.categories", Column: "parent_id"}, Target: dberd.TableColumn{Table: "public.categories", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.comments", Column: "post_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.comments", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.post_categories", Column: "category_id"}, Target: dberd.TableColumn{Table: "public.categories", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.post_categories", Column: "post_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.posts", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "role_id"}, Target: dberd.TableColumn{Table: "public.roles", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}},
+			{Source: dberd.TableColumn{Table: "public.categories", Column: "parent_id"}, Target: dberd.TableColumn{Table: "public.categories", Column: "id"}, Cardinality: dberd.CardinalityManyToOne, Optional: true},
+			{Source: dberd.TableColumn{Table: "public.comments", Column: "post_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+			{Source: dberd.TableColumn{Table: "public.comments", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+			{Source: dberd.TableColumn{Table: "public.post_categories", Column: "category_id"}, Target: dberd.TableColumn{Table: "public.categories", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+			{Source: dberd.TableColumn{Table: "public.post_categories", Column: "post_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+			{Source: dberd.TableColumn{Table: "public.posts", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "role_id"}, Target: dberd.TableColumn{Table: "public.roles", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
 		},
 	}
 
 	expected.Sort()
 
 	assert.Equal(t, expected, actual)
+
+	withStats, err := NewSourceFromDB(db, WithStats()).ExtractSchema(ctx)
+	require.NoError(t, err)
+
+	for _, table := range withStats.Tables {
+		assert.NotNil(t, table.Stats, table.Name)
+	}
+}
+
+func TestApplyLocalityRows(t *testing.T) {
+	t.Parallel()
+
+	tables := []dberd.Table{
+		{Name: "public.users"},
+		{Name: "public.countries"},
+		{Name: "public.events"},
+		{Name: "public.orders"},
+		{Name: "public.plain"},
+	}
+
+	localityRows := []localityRow{
+		{
+			tableSchema:     "public",
+			tableName:       "users",
+			createStatement: "CREATE TABLE public.users (\n\tid INT8 NOT NULL,\n\tCONSTRAINT users_pkey PRIMARY KEY (id ASC)\n) LOCALITY REGIONAL BY ROW",
+		},
+		{
+			tableSchema:     "public",
+			tableName:       "countries",
+			createStatement: "CREATE TABLE public.countries (\n\tcode STRING NOT NULL,\n\tCONSTRAINT countries_pkey PRIMARY KEY (code ASC)\n) LOCALITY GLOBAL",
+		},
+		{
+			tableSchema:     "public",
+			tableName:       "events",
+			createStatement: "CREATE TABLE public.events (\n\tts TIMESTAMP NOT NULL,\n\tCONSTRAINT events_pkey PRIMARY KEY (ts ASC) USING HASH WITH (bucket_count=8)\n) LOCALITY REGIONAL BY TABLE IN PRIMARY REGION",
+		},
+		{
+			tableSchema:     "public",
+			tableName:       "orders",
+			createStatement: "CREATE TABLE public.orders (\n\tid INT8 NOT NULL,\n\tCONSTRAINT orders_pkey PRIMARY KEY (id ASC) USING HASH\n)",
+		},
+		{
+			tableSchema:     "public",
+			tableName:       "unknown",
+			createStatement: "CREATE TABLE public.unknown (\n\tid INT8 NOT NULL\n) LOCALITY GLOBAL",
+		},
+	}
+
+	applyLocalityRows(tables, localityRows)
+
+	expected := []dberd.Table{
+		{Name: "public.users", Locality: "REGIONAL BY ROW"},
+		{Name: "public.countries", Locality: "GLOBAL"},
+		{Name: "public.events", Locality: "REGIONAL BY TABLE IN PRIMARY REGION", PrimaryKeyShardBuckets: 8},
+		{Name: "public.orders", PrimaryKeyShardBuckets: 16},
+		{Name: "public.plain"},
+	}
+
+	assert.Equal(t, expected, tables)
 }
 
 func setupTestDB(t *testing.T) (testcontainers.Container, *sql.DB) {
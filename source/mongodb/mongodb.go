@@ -21,32 +21,88 @@ var (
 	_ dberd.Source = (*Source)(nil)
 )
 
+func init() {
+	dberd.RegisterSource(dberd.SourceRegistration{
+		Name:        "mongodb",
+		Description: "MongoDB database, collections are extracted as tables",
+		Options: []dberd.OptionSpec{
+			{Name: "stats", Description: "Extract document counts and on-disk sizes of collections", Default: "false"},
+		},
+		New:     newRegisteredSource,
+		Schemes: []string{"mongodb", "mongodb+srv"},
+	})
+}
+
+// newRegisteredSource creates a Source from registry options.
+func newRegisteredSource(dsn string, opts dberd.Options) (dberd.Source, error) {
+	stats, err := opts.Bool("stats")
+	if err != nil {
+		return nil, err
+	}
+
+	var sourceOpts []SourceOpt
+	if stats {
+		sourceOpts = append(sourceOpts, WithStats())
+	}
+
+	s, err := NewSource(dsn, sourceOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
 // Source represents a MongoDB database source for schema extraction.
 type Source struct {
 	client *mongo.Client
 	closer io.Closer
+
+	extractStats bool
+}
+
+// SourceOpt is a function type that allows customization of a Source instance.
+type SourceOpt func(*Source)
+
+// WithStats returns a SourceOpt that extracts document counts and on-disk sizes of collections.
+func WithStats() SourceOpt {
+	return func(s *Source) {
+		s.extractStats = true
+	}
 }
 
 // NewSource creates a new MongoDB source from a connection string.
-func NewSource(connStr string) (*Source, error) {
+func NewSource(connStr string, opts ...SourceOpt) (*Source, error) {
 	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(connStr))
 	if err != nil {
 		return nil, fmt.Errorf("connecting to mongodb: %w", err)
 	}
 
-	return &Source{
+	s := &Source{
 		client: client,
 		closer: &mongoCloser{client: client},
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
 }
 
 // NewSourceFromClient creates a new MongoDB source from an existing client.
 // This is useful when you want to reuse an existing MongoDB client
 // for schema extraction purposes.
-func NewSourceFromClient(client *mongo.Client) *Source {
-	return &Source{
+func NewSourceFromClient(client *mongo.Client, opts ...SourceOpt) *Source {
+	s := &Source{
 		client: client,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Close closes the MongoDB client if it was created by NewSource.
@@ -70,6 +126,11 @@ func (c *mongoCloser) Close() error {
 
 // ExtractSchema extracts the complete database schema including collections.
 func (s *Source) ExtractSchema(ctx context.Context) (schema dberd.Schema, err error) {
+	schema.Metadata, err = s.extractMetadata(ctx)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("extracting metadata: %w", err)
+	}
+
 	schema.Tables, err = s.extractCollections(ctx)
 	if err != nil {
 		return dberd.Schema{}, fmt.Errorf("extracting collections: %w", err)
@@ -78,6 +139,21 @@ func (s *Source) ExtractSchema(ctx context.Context) (schema dberd.Schema, err er
 	return schema, nil
 }
 
+// extractMetadata queries the server build info for its version.
+// The database name is left empty, as collections of all databases are extracted.
+func (s *Source) extractMetadata(ctx context.Context) (*dberd.Metadata, error) {
+	var buildInfo struct {
+		Version string `bson:"version"`
+	}
+
+	err := s.client.Database("admin").RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&buildInfo)
+	if err != nil {
+		return nil, fmt.Errorf("running buildInfo command: %w", err)
+	}
+
+	return dberd.NewMetadata("mongodb", buildInfo.Version, ""), nil
+}
+
 // extractCollections queries the database for collection information and converts it to dberd.Table format.
 func (s *Source) extractCollections(ctx context.Context) ([]dberd.Table, error) {
 	databases, err := s.client.ListDatabaseNames(ctx, bson.M{})
@@ -111,16 +187,44 @@ func (s *Source) extractCollections(ctx context.Context) ([]dberd.Table, error)
 				return nil, fmt.Errorf("getting schema for collection %s: %w", collName, err)
 			}
 
-			tables = append(tables, dberd.Table{
+			table := dberd.Table{
 				Name:    fmt.Sprintf("%s.%s", dbName, collName),
 				Columns: schema,
-			})
+			}
+
+			if s.extractStats {
+				table.Stats, err = s.getCollectionStats(ctx, db, collName)
+				if err != nil {
+					return nil, fmt.Errorf("getting stats for collection %s: %w", collName, err)
+				}
+			}
+
+			tables = append(tables, table)
 		}
 	}
 
 	return tables, nil
 }
 
+// getCollectionStats extracts the collection document count and on-disk size including indexes.
+func (s *Source) getCollectionStats(ctx context.Context, db *mongo.Database, collName string) (*dberd.TableStats, error) {
+	var collStats struct {
+		Count          int64 `bson:"count"`
+		StorageSize    int64 `bson:"storageSize"`
+		TotalIndexSize int64 `bson:"totalIndexSize"`
+	}
+
+	err := db.RunCommand(ctx, bson.D{{Key: "collStats", Value: collName}}).Decode(&collStats)
+	if err != nil {
+		return nil, fmt.Errorf("running collStats command: %w", err)
+	}
+
+	return &dberd.TableStats{
+		Rows:      collStats.Count,
+		SizeBytes: collStats.StorageSize + collStats.TotalIndexSize,
+	}, nil
+}
+
 // getCollectionSchema extracts the schema of a collection by sampling documents.
 func (s *Source) getCollectionSchema(ctx context.Context, coll *mongo.Collection) ([]dberd.Column, error) {
 	// Sample a document to infer schema
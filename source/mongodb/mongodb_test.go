@@ -76,6 +76,15 @@ func TestExtractSchema(t *testing.T) {
 
 	actual.Sort()
 
+	require.NotNil(t, actual.Metadata)
+	assert.Equal(t, "mongodb", actual.Metadata.Source)
+	assert.NotEmpty(t, actual.Metadata.ServerVersion)
+	assert.Equal(t, dberd.Version, actual.Metadata.Version)
+	assert.False(t, actual.Metadata.ExtractedAt.IsZero())
+
+	// Metadata depends on the server and extraction time, so it is checked separately.
+	actual.Metadata = nil
+
 	expected := dberd.Schema{
 		Tables: []dberd.Table{
 			{
@@ -107,6 +116,15 @@ func TestExtractSchema(t *testing.T) {
 	expected.Sort()
 
 	assert.Equal(t, expected, actual)
+
+	withStats, err := NewSourceFromClient(client, WithStats()).ExtractSchema(ctx)
+	require.NoError(t, err)
+
+	for _, table := range withStats.Tables {
+		require.NotNil(t, table.Stats, table.Name)
+		assert.Equal(t, int64(1), table.Stats.Rows, table.Name)
+		assert.Positive(t, table.Stats.SizeBytes, table.Name)
+	}
 }
 
 func setupTestDB(t *testing.T) (testcontainers.Container, *mongo.Client) {
@@ -19,14 +19,102 @@ var (
 	_ dberd.Source = (*Source)(nil)
 )
 
+func init() {
+	dberd.RegisterSource(dberd.SourceRegistration{
+		Name:        "postgres",
+		Description: "PostgreSQL database",
+		Options: []dberd.OptionSpec{
+			{Name: "partitions", Description: "List partitions of partitioned tables", Default: "false"},
+			{Name: "routines", Description: "Extract sequences, functions and triggers", Default: "false"},
+			{Name: "security", Description: "Extract row-level security policies and table grants", Default: "false"},
+			{Name: "stats", Description: "Extract estimated row counts and on-disk sizes of tables", Default: "false"},
+		},
+		New:     newRegisteredSource,
+		Schemes: []string{"postgres", "postgresql"},
+	})
+}
+
+// newRegisteredSource creates a Source from registry options.
+func newRegisteredSource(dsn string, opts dberd.Options) (dberd.Source, error) {
+	flags := []struct {
+		name string
+		opt  func() SourceOpt
+	}{
+		{"partitions", WithPartitionList},
+		{"routines", WithRoutines},
+		{"security", WithSecurity},
+		{"stats", WithStats},
+	}
+
+	var sourceOpts []SourceOpt
+
+	for _, flag := range flags {
+		enabled, err := opts.Bool(flag.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if enabled {
+			sourceOpts = append(sourceOpts, flag.opt())
+		}
+	}
+
+	s, err := NewSource(dsn, sourceOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
 // Source represents a PostgreSQL database source for schema extraction.
 type Source struct {
 	db     *sql.DB
 	closer io.Closer
+
+	listPartitions  bool
+	extractRoutines bool
+	extractSecurity bool
+	extractStats    bool
+}
+
+// SourceOpt is a function type that allows customization of a Source instance.
+type SourceOpt func(*Source)
+
+// WithPartitionList returns a SourceOpt that lists partitions of partitioned tables
+// in their parent table partitioning metadata, so targets can show them as a sub-note.
+// Partitions are never extracted as separate tables.
+func WithPartitionList() SourceOpt {
+	return func(s *Source) {
+		s.listPartitions = true
+	}
+}
+
+// WithRoutines returns a SourceOpt that extracts sequences, functions and triggers
+// into the schema routines section.
+func WithRoutines() SourceOpt {
+	return func(s *Source) {
+		s.extractRoutines = true
+	}
+}
+
+// WithSecurity returns a SourceOpt that extracts row-level security flags, policies
+// and table privileges granted to roles.
+func WithSecurity() SourceOpt {
+	return func(s *Source) {
+		s.extractSecurity = true
+	}
+}
+
+// WithStats returns a SourceOpt that extracts estimated row counts and on-disk sizes of tables.
+func WithStats() SourceOpt {
+	return func(s *Source) {
+		s.extractStats = true
+	}
 }
 
 // NewSource creates a new PostgreSQL source from a connection string.
-func NewSource(connStr string) (*Source, error) {
+func NewSource(connStr string, opts ...SourceOpt) (*Source, error) {
 	pgConfig, err := pgx.ParseConfig(connStr)
 	if err != nil {
 		return nil, fmt.Errorf("parsing postgres connection string: %w", err)
@@ -35,19 +123,31 @@ func NewSource(connStr string) (*Source, error) {
 	pgConnector := stdlib.GetConnector(*pgConfig)
 	db := sql.OpenDB(pgConnector)
 
-	return &Source{
+	s := &Source{
 		db:     db,
 		closer: db,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
 }
 
 // NewSourceFromDB creates a new PostgreSQL source from an existing database connection.
 // This is useful when you want to reuse an existing database connection
 // for schema extraction purposes.
-func NewSourceFromDB(db *sql.DB) *Source {
-	return &Source{
+func NewSourceFromDB(db *sql.DB, opts ...SourceOpt) *Source {
+	s := &Source{
 		db: db,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Close closes the database connection if it was created by NewSource.
@@ -62,68 +162,151 @@ func (s *Source) Close() error {
 
 // ExtractSchema extracts the complete database schema including tables and their references.
 func (s *Source) ExtractSchema(ctx context.Context) (schema dberd.Schema, err error) {
+	schema.Metadata, err = s.extractMetadata(ctx)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("extracting metadata: %w", err)
+	}
+
 	schema.Tables, err = s.extractTables(ctx)
 	if err != nil {
 		return dberd.Schema{}, fmt.Errorf("extracting tables: %w", err)
 	}
 
+	err = s.extractPartitioning(ctx, schema.Tables)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("extracting partitioning: %w", err)
+	}
+
+	err = s.extractIndexes(ctx, schema.Tables)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("extracting indexes: %w", err)
+	}
+
+	if s.extractStats {
+		err = s.extractTableStats(ctx, schema.Tables)
+		if err != nil {
+			return dberd.Schema{}, fmt.Errorf("extracting table stats: %w", err)
+		}
+	}
+
 	schema.References, err = s.extractReferences(ctx)
 	if err != nil {
 		return dberd.Schema{}, fmt.Errorf("extracting references: %w", err)
 	}
 
+	inheritances, err := s.extractInheritances(ctx)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("extracting inheritances: %w", err)
+	}
+
+	schema.References = append(schema.References, inheritances...)
+
+	schema.ComputeCardinality()
+
+	if s.extractRoutines {
+		schema.Routines, err = s.extractRoutinesSection(ctx)
+		if err != nil {
+			return dberd.Schema{}, fmt.Errorf("extracting routines: %w", err)
+		}
+	}
+
+	if s.extractSecurity {
+		err = s.extractRowSecurity(ctx, schema.Tables)
+		if err != nil {
+			return dberd.Schema{}, fmt.Errorf("extracting row security: %w", err)
+		}
+
+		schema.Grants, err = s.extractGrants(ctx)
+		if err != nil {
+			return dberd.Schema{}, fmt.Errorf("extracting grants: %w", err)
+		}
+	}
+
 	return schema, nil
 }
 
-const extractTablesQuery = `
-	WITH pk_columns AS (
-    	SELECT 
-    	    kcu.table_schema,
-    	    kcu.table_name,
-    	    kcu.column_name
-    	FROM information_schema.key_column_usage kcu
-    	JOIN information_schema.table_constraints tc
-    	    ON tc.constraint_name = kcu.constraint_name
-    	    AND tc.table_schema = kcu.table_schema
-    	WHERE tc.constraint_type = 'PRIMARY KEY'
-    	ORDER BY kcu.table_schema, kcu.table_name, kcu.column_name
+const extractMetadataQuery = `
+	SELECT current_database(), current_setting('server_version'), current_setting('server_version_num')::int;`
+
+// minServerVersion is the server_version_num of PostgreSQL 12, the oldest supported release.
+// Extraction queries use catalog columns and functions added up to it, e.g. pg_attribute.attgenerated,
+// pg_index.indnkeyatts, pg_constraint.conparentid, pg_proc.prokind and pg_partition_tree.
+const minServerVersion = 120000
+
+// extractMetadata queries the database for its name and server version,
+// failing on servers older than minServerVersion.
+func (s *Source) extractMetadata(ctx context.Context) (*dberd.Metadata, error) {
+	var (
+		database, serverVersion string
+		serverVersionNum        int
 	)
+
+	if err := s.db.QueryRowContext(ctx, extractMetadataQuery).Scan(&database, &serverVersion, &serverVersionNum); err != nil {
+		return nil, fmt.Errorf("querying metadata: %w", err)
+	}
+
+	if err := checkServerVersion(serverVersionNum, serverVersion); err != nil {
+		return nil, err
+	}
+
+	return dberd.NewMetadata("postgres", serverVersion, database), nil
+}
+
+// checkServerVersion returns an error if the server is older than minServerVersion.
+func checkServerVersion(versionNum int, version string) error {
+	if versionNum < minServerVersion {
+		return fmt.Errorf("unsupported server version %s, PostgreSQL 12 or later is required", version)
+	}
+
+	return nil
+}
+
+// extractTablesQuery reads columns straight from pg_catalog rather than information_schema,
+// which scales to large catalogs, is not limited by the current role privileges
+// and reports precise types via format_type.
+const extractTablesQuery = `
 	SELECT
-	    c.table_schema,
-	    c.table_name,
-	    c.column_name,
-	    c.data_type,
-	    c.is_nullable,
-	    c.column_default,
-	    pgd.description as column_comment,
-	    EXISTS (
-	        SELECT 1 
-	        FROM pk_columns pk 
-	        WHERE pk.table_schema = c.table_schema 
-	        AND pk.table_name = c.table_name 
-	        AND pk.column_name = c.column_name
-	    ) AS is_primary
-	FROM information_schema.columns c
-	JOIN information_schema.tables t ON c.table_schema = t.table_schema AND c.table_name = t.table_name
-	LEFT JOIN pg_catalog.pg_statio_all_tables st ON st.schemaname = c.table_schema AND st.relname = c.table_name
-	LEFT JOIN pg_catalog.pg_description pgd ON pgd.objoid = st.relid AND pgd.objsubid = c.ordinal_position
-	WHERE c.table_schema NOT IN ('pg_catalog', 'information_schema')
-	AND t.table_type = 'BASE TABLE'
-	ORDER BY c.table_schema, c.table_name, c.ordinal_position;`
+		n.nspname AS table_schema,
+		c.relname AS table_name,
+		a.attname AS column_name,
+		format_type(a.atttypid, a.atttypmod) AS data_type,
+		a.attnotnull AS is_not_null,
+		pg_get_expr(d.adbin, d.adrelid) AS column_default,
+		col_description(c.oid, a.attnum) AS column_comment,
+		COALESCE(a.attnum = ANY(pk.conkey), false) AS is_primary,
+		a.attgenerated = 's' AS is_generated,
+		fs.srvname AS foreign_server
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid
+	LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+	LEFT JOIN pg_catalog.pg_constraint pk ON pk.conrelid = c.oid AND pk.contype = 'p'
+	LEFT JOIN pg_catalog.pg_foreign_table ft ON ft.ftrelid = c.oid
+	LEFT JOIN pg_catalog.pg_foreign_server fs ON fs.oid = ft.ftserver
+	WHERE c.relkind IN ('r', 'p', 'f')
+	AND NOT c.relispartition
+	AND a.attnum > 0
+	AND NOT a.attisdropped
+	AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+	AND n.nspname NOT LIKE 'pg\_toast%'
+	AND n.nspname NOT LIKE 'pg\_temp\_%'
+	ORDER BY n.nspname, c.relname, a.attnum;`
 
 type tableRow struct {
 	tableSchema   string
 	tableName     string
 	columnName    string
 	dataType      string
-	isNullable    string
+	isNotNull     bool
 	columnDefault *string
 	columnComment *string
 	isPrimary     bool
+	isGenerated   bool
+	foreignServer *string
 }
 
 // extractTables queries the database for table and column information and converts it to dberd.Table format.
-// It excludes system schemas and hidden columns.
+// It includes foreign tables and excludes system schemas, partitions and dropped columns.
 func (s *Source) extractTables(ctx context.Context) ([]dberd.Table, error) {
 	rows, err := s.db.QueryContext(ctx, extractTablesQuery)
 	if err != nil {
@@ -140,10 +323,12 @@ func (s *Source) extractTables(ctx context.Context) ([]dberd.Table, error) {
 			&r.tableName,
 			&r.columnName,
 			&r.dataType,
-			&r.isNullable,
+			&r.isNotNull,
 			&r.columnDefault,
 			&r.columnComment,
 			&r.isPrimary,
+			&r.isGenerated,
+			&r.foreignServer,
 		); err != nil {
 			return nil, fmt.Errorf("scanning tables row: %w", err)
 		}
@@ -172,22 +357,35 @@ func tableRowsToSchemaTables(tableRows []tableRow) []dberd.Table {
 				Name:    tableKey,
 				Columns: make([]dberd.Column, 0, 10),
 			}
+			if row.foreignServer != nil {
+				table.ForeignServer = *row.foreignServer
+			}
 			tableMap[tableKey] = table
 		}
 
+		column := dberd.Column{
+			Name:      row.columnName,
+			IsPrimary: row.isPrimary,
+			NotNull:   row.isNotNull,
+		}
+
 		definition := strings.ToUpper(row.dataType)
-		if row.isNullable == "NO" {
+		if row.isGenerated && row.columnDefault != nil {
+			// Generation expressions are stored as column defaults in pg_attrdef.
+			column.Generated = &dberd.GeneratedColumn{
+				Expression: *row.columnDefault,
+				Stored:     true,
+			}
+			definition += " GENERATED ALWAYS AS (" + *row.columnDefault + ") STORED"
+		}
+		if row.isNotNull {
 			definition += " NOT NULL"
 		}
-		if row.columnDefault != nil && *row.columnDefault != "" {
+		if !row.isGenerated && row.columnDefault != nil && *row.columnDefault != "" {
 			definition += " DEFAULT " + *row.columnDefault
 		}
 
-		column := dberd.Column{
-			Name:       row.columnName,
-			Definition: definition,
-			IsPrimary:  row.isPrimary,
-		}
+		column.Definition = definition
 
 		if row.columnComment != nil {
 			column.Comment = *row.columnComment
@@ -205,6 +403,307 @@ func tableRowsToSchemaTables(tableRows []tableRow) []dberd.Table {
 	return tables
 }
 
+const extractPartitioningQuery = `
+	SELECT
+		pn.nspname AS table_schema,
+		p.relname AS table_name,
+		pg_get_partkeydef(p.oid) AS partition_key,
+		cn.nspname AS partition_schema,
+		c.relname AS partition_name,
+		pg_get_expr(c.relpartbound, c.oid) AS partition_bound
+	FROM pg_class p
+	JOIN pg_namespace pn ON pn.oid = p.relnamespace
+	LEFT JOIN pg_inherits i ON i.inhparent = p.oid
+	LEFT JOIN pg_class c ON c.oid = i.inhrelid
+	LEFT JOIN pg_namespace cn ON cn.oid = c.relnamespace
+	WHERE p.relkind = 'p'
+	AND NOT p.relispartition
+	AND pn.nspname NOT IN ('pg_catalog', 'information_schema')
+	ORDER BY pn.nspname, p.relname, cn.nspname, c.relname;`
+
+type partitionRow struct {
+	tableSchema     string
+	tableName       string
+	partitionKey    string
+	partitionSchema *string
+	partitionName   *string
+	partitionBound  *string
+}
+
+// extractPartitioning queries the database for partitioned tables and sets their
+// partitioning metadata on the given tables. Partitions are listed only if enabled via WithPartitionList.
+func (s *Source) extractPartitioning(ctx context.Context, tables []dberd.Table) error {
+	rows, err := s.db.QueryContext(ctx, extractPartitioningQuery)
+	if err != nil {
+		return fmt.Errorf("querying partitioning: %w", err)
+	}
+	defer rows.Close()
+
+	var partitionRows []partitionRow
+
+	for rows.Next() {
+		var r partitionRow
+		if err := rows.Scan(
+			&r.tableSchema,
+			&r.tableName,
+			&r.partitionKey,
+			&r.partitionSchema,
+			&r.partitionName,
+			&r.partitionBound,
+		); err != nil {
+			return fmt.Errorf("scanning partitioning row: %w", err)
+		}
+
+		partitionRows = append(partitionRows, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("partitioning rows error: %w", err)
+	}
+
+	applyPartitionRows(tables, partitionRows, s.listPartitions)
+
+	return nil
+}
+
+// applyPartitionRows sets partitioning metadata from a slice of partitionRow on the matching tables.
+// The partition key definition, e.g. "RANGE (created_at)", is split into strategy and key.
+func applyPartitionRows(tables []dberd.Table, partitionRows []partitionRow, listPartitions bool) {
+	tableIndex := make(map[string]int, len(tables))
+	for i := range tables {
+		tableIndex[tables[i].Name] = i
+	}
+
+	for _, row := range partitionRows {
+		i, ok := tableIndex[row.tableSchema+"."+row.tableName]
+		if !ok {
+			continue
+		}
+
+		if tables[i].Partitioning == nil {
+			strategy, key, _ := strings.Cut(row.partitionKey, " ")
+
+			tables[i].Partitioning = &dberd.Partitioning{
+				Strategy: strategy,
+				Key:      strings.TrimSuffix(strings.TrimPrefix(key, "("), ")"),
+			}
+		}
+
+		if !listPartitions || row.partitionName == nil {
+			continue
+		}
+
+		partition := dberd.Partition{
+			Name: *row.partitionName,
+		}
+
+		if row.partitionSchema != nil {
+			partition.Name = *row.partitionSchema + "." + *row.partitionName
+		}
+
+		if row.partitionBound != nil {
+			partition.Bound = *row.partitionBound
+		}
+
+		tables[i].Partitioning.Partitions = append(tables[i].Partitioning.Partitions, partition)
+	}
+}
+
+// extractIndexesQuery lists key columns of every index, INCLUDE columns are skipped.
+// Expression key parts have no column.
+const extractIndexesQuery = `
+	SELECT
+		n.nspname AS table_schema,
+		t.relname AS table_name,
+		i.relname AS index_name,
+		a.attname AS column_name,
+		ix.indisunique AS is_unique,
+		ix.indisprimary AS is_primary,
+		upper(am.amname) AS index_type
+	FROM pg_catalog.pg_index ix
+	JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid
+	JOIN pg_catalog.pg_class t ON t.oid = ix.indrelid
+	JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+	JOIN pg_catalog.pg_am am ON am.oid = i.relam
+	CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+	LEFT JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+	WHERE t.relkind IN ('r', 'p')
+	AND NOT t.relispartition
+	AND k.ord <= ix.indnkeyatts
+	AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+	AND n.nspname NOT LIKE 'pg\_toast%'
+	ORDER BY n.nspname, t.relname, i.relname, k.ord;`
+
+type indexRow struct {
+	tableSchema string
+	tableName   string
+	indexName   string
+	columnName  *string
+	isUnique    bool
+	isPrimary   bool
+	indexType   string
+}
+
+// extractIndexes queries the database for table indexes and sets them on the given tables.
+func (s *Source) extractIndexes(ctx context.Context, tables []dberd.Table) error {
+	rows, err := s.db.QueryContext(ctx, extractIndexesQuery)
+	if err != nil {
+		return fmt.Errorf("querying indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexRows []indexRow
+
+	for rows.Next() {
+		var r indexRow
+		if err := rows.Scan(
+			&r.tableSchema,
+			&r.tableName,
+			&r.indexName,
+			&r.columnName,
+			&r.isUnique,
+			&r.isPrimary,
+			&r.indexType,
+		); err != nil {
+			return fmt.Errorf("scanning indexes row: %w", err)
+		}
+
+		indexRows = append(indexRows, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("indexes rows error: %w", err)
+	}
+
+	applyIndexRows(tables, indexRows)
+
+	return nil
+}
+
+// applyIndexRows groups a slice of indexRow by table and index and sets the resulting
+// dberd.Index values on the matching tables. Rows must be ordered by column position.
+func applyIndexRows(tables []dberd.Table, indexRows []indexRow) {
+	tableIndex := make(map[string]int, len(tables))
+	for i := range tables {
+		tableIndex[tables[i].Name] = i
+	}
+
+	indexPositions := make(map[string]int, len(indexRows))
+
+	for _, row := range indexRows {
+		tableKey := row.tableSchema + "." + row.tableName
+
+		i, ok := tableIndex[tableKey]
+		if !ok {
+			continue
+		}
+
+		indexKey := tableKey + "." + row.indexName
+
+		pos, exists := indexPositions[indexKey]
+		if !exists {
+			tables[i].Indexes = append(tables[i].Indexes, dberd.Index{
+				Name:      row.indexName,
+				Type:      row.indexType,
+				IsUnique:  row.isUnique,
+				IsPrimary: row.isPrimary,
+			})
+			pos = len(tables[i].Indexes) - 1
+			indexPositions[indexKey] = pos
+		}
+
+		if row.columnName != nil {
+			tables[i].Indexes[pos].Columns = append(tables[i].Indexes[pos].Columns, *row.columnName)
+		}
+	}
+}
+
+// extractTableStatsQuery reads planner estimates, which are cheap but only as fresh as the last
+// ANALYZE. Partitioned tables sum up estimates of their leaf partitions.
+const extractTableStatsQuery = `
+	SELECT
+		n.nspname AS table_schema,
+		c.relname AS table_name,
+		CASE WHEN c.relkind = 'p' THEN (
+			SELECT COALESCE(SUM(GREATEST(pc.reltuples, 0)), 0)
+			FROM pg_partition_tree(c.oid) pt
+			JOIN pg_catalog.pg_class pc ON pc.oid = pt.relid
+			WHERE pt.isleaf
+		) ELSE GREATEST(c.reltuples, 0) END::bigint AS row_estimate,
+		CASE WHEN c.relkind = 'p' THEN (
+			SELECT COALESCE(SUM(pg_total_relation_size(pt.relid)), 0)
+			FROM pg_partition_tree(c.oid) pt
+			WHERE pt.isleaf
+		) ELSE pg_total_relation_size(c.oid) END::bigint AS size_bytes
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	WHERE c.relkind IN ('r', 'p')
+	AND NOT c.relispartition
+	AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+	AND n.nspname NOT LIKE 'pg\_toast%'
+	AND n.nspname NOT LIKE 'pg\_temp\_%';`
+
+type tableStatsRow struct {
+	tableSchema string
+	tableName   string
+	rows        int64
+	sizeBytes   int64
+}
+
+// extractTableStats queries the database for estimated table row counts and sizes
+// and applies them to the given tables.
+func (s *Source) extractTableStats(ctx context.Context, tables []dberd.Table) error {
+	rows, err := s.db.QueryContext(ctx, extractTableStatsQuery)
+	if err != nil {
+		return fmt.Errorf("querying table stats: %w", err)
+	}
+	defer rows.Close()
+
+	var tableStatsRows []tableStatsRow
+
+	for rows.Next() {
+		var r tableStatsRow
+		if err := rows.Scan(
+			&r.tableSchema,
+			&r.tableName,
+			&r.rows,
+			&r.sizeBytes,
+		); err != nil {
+			return fmt.Errorf("scanning table stats row: %w", err)
+		}
+
+		tableStatsRows = append(tableStatsRows, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("table stats rows error: %w", err)
+	}
+
+	applyTableStatsRows(tables, tableStatsRows)
+
+	return nil
+}
+
+// applyTableStatsRows sets statistics on the matching tables.
+func applyTableStatsRows(tables []dberd.Table, tableStatsRows []tableStatsRow) {
+	tableIndex := make(map[string]int, len(tables))
+	for i := range tables {
+		tableIndex[tables[i].Name] = i
+	}
+
+	for _, row := range tableStatsRows {
+		i, ok := tableIndex[row.tableSchema+"."+row.tableName]
+		if !ok {
+			continue
+		}
+
+		tables[i].Stats = &dberd.TableStats{
+			Rows:      row.rows,
+			SizeBytes: row.sizeBytes,
+		}
+	}
+}
+
 const extractReferencesQuery = `
 	WITH foreign_keys AS (
 		SELECT
@@ -228,6 +727,7 @@ const extractReferencesQuery = `
 		JOIN LATERAL unnest(con.confkey) WITH ORDINALITY AS tgt_cols(attnum, ord) ON src_cols.ord = tgt_cols.ord
 		JOIN pg_attribute tgt_col ON tgt_col.attrelid = tgt_tbl.oid AND tgt_col.attnum = tgt_cols.attnum
 		WHERE con.contype = 'f'
+		AND con.conparentid = 0
 	)
 	SELECT 
 		source_schema,
@@ -304,3 +804,64 @@ func referenceRowsToSchemaReferences(referenceRows []referenceRow) []dberd.Refer
 
 	return references
 }
+
+const extractInheritancesQuery = `
+	SELECT
+		cn.nspname AS child_schema,
+		c.relname AS child_table,
+		pn.nspname AS parent_schema,
+		p.relname AS parent_table
+	FROM pg_catalog.pg_inherits i
+	JOIN pg_catalog.pg_class c ON c.oid = i.inhrelid
+	JOIN pg_catalog.pg_namespace cn ON cn.oid = c.relnamespace
+	JOIN pg_catalog.pg_class p ON p.oid = i.inhparent
+	JOIN pg_catalog.pg_namespace pn ON pn.oid = p.relnamespace
+	WHERE c.relkind IN ('r', 'f')
+	AND p.relkind IN ('r', 'f')
+	AND NOT c.relispartition
+	AND cn.nspname NOT IN ('pg_catalog', 'information_schema')
+	ORDER BY cn.nspname, c.relname, i.inhseqno;`
+
+type inheritanceRow struct {
+	childSchema  string
+	childTable   string
+	parentSchema string
+	parentTable  string
+}
+
+// extractInheritances queries the database for classic table inheritance (INHERITS)
+// and converts it to dberd.Reference format. Partitions are excluded, as they are folded
+// into their partitioned parent table.
+func (s *Source) extractInheritances(ctx context.Context) ([]dberd.Reference, error) {
+	rows, err := s.db.QueryContext(ctx, extractInheritancesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("querying inheritances: %w", err)
+	}
+	defer rows.Close()
+
+	var references []dberd.Reference
+
+	for rows.Next() {
+		var r inheritanceRow
+		if err := rows.Scan(
+			&r.childSchema,
+			&r.childTable,
+			&r.parentSchema,
+			&r.parentTable,
+		); err != nil {
+			return nil, fmt.Errorf("scanning inheritances row: %w", err)
+		}
+
+		references = append(references, dberd.Reference{
+			Source: dberd.TableColumn{Table: r.childSchema + "." + r.childTable},
+			Target: dberd.TableColumn{Table: r.parentSchema + "." + r.parentTable},
+			Kind:   dberd.ReferenceKindInheritance,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("inheritances rows error: %w", err)
+	}
+
+	return references, nil
+}
@@ -60,6 +60,7 @@ func TestExtractSchema(t *testing.T) {
 			id SERIAL PRIMARY KEY,
 			user_id INTEGER NOT NULL,
 			title VARCHAR(255) NOT NULL,
+			title_length INTEGER GENERATED ALWAYS AS (length(title)) STORED,
 			content TEXT,
 			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (user_id) REFERENCES users(id)
@@ -105,81 +106,508 @@ func TestExtractSchema(t *testing.T) {
 
 	actual.Sort()
 
+	require.NotNil(t, actual.Metadata)
+	assert.Equal(t, "postgres", actual.Metadata.Source)
+	assert.NotEmpty(t, actual.Metadata.ServerVersion)
+	assert.Equal(t, dberd.Version, actual.Metadata.Version)
+	assert.False(t, actual.Metadata.ExtractedAt.IsZero())
+
+	// Metadata depends on the server and extraction time, so it is checked separately.
+	actual.Metadata = nil
+
 	expected := dberd.Schema{
 		Tables: []dberd.Table{
 			{
 				Name: "public.users",
 				Columns: []dberd.Column{
-					{Name: "id", Definition: "INTEGER NOT NULL DEFAULT nextval('users_id_seq'::regclass)", IsPrimary: true},
-					{Name: "name", Definition: "CHARACTER VARYING NOT NULL"},
-					{Name: "email", Definition: "CHARACTER VARYING NOT NULL", Comment: "User email address"},
+					{Name: "id", Definition: "INTEGER NOT NULL DEFAULT nextval('users_id_seq'::regclass)", IsPrimary: true, NotNull: true},
+					{Name: "name", Definition: "CHARACTER VARYING(255) NOT NULL", NotNull: true},
+					{Name: "email", Definition: "CHARACTER VARYING(255) NOT NULL", Comment: "User email address", NotNull: true},
 					{Name: "created_at", Definition: "TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP"},
 				},
+				Indexes: []dberd.Index{
+					{Name: "users_pkey", Columns: []string{"id"}, Type: "BTREE", IsUnique: true, IsPrimary: true},
+				},
 			},
 			{
 				Name: "public.roles",
 				Columns: []dberd.Column{
-					{Name: "id", Definition: "INTEGER NOT NULL DEFAULT nextval('roles_id_seq'::regclass)", IsPrimary: true},
-					{Name: "name", Definition: "CHARACTER VARYING NOT NULL"},
+					{Name: "id", Definition: "INTEGER NOT NULL DEFAULT nextval('roles_id_seq'::regclass)", IsPrimary: true, NotNull: true},
+					{Name: "name", Definition: "CHARACTER VARYING(50) NOT NULL", NotNull: true},
 					{Name: "description", Definition: "TEXT", Comment: "Role description and permissions"},
 					{Name: "created_at", Definition: "TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP"},
 				},
+				Indexes: []dberd.Index{
+					{Name: "roles_pkey", Columns: []string{"id"}, Type: "BTREE", IsUnique: true, IsPrimary: true},
+				},
 			},
 			{
 				Name: "public.user_roles",
 				Columns: []dberd.Column{
-					{Name: "user_id", Definition: "INTEGER NOT NULL", IsPrimary: true},
-					{Name: "role_id", Definition: "INTEGER NOT NULL", IsPrimary: true},
+					{Name: "user_id", Definition: "INTEGER NOT NULL", IsPrimary: true, NotNull: true},
+					{Name: "role_id", Definition: "INTEGER NOT NULL", IsPrimary: true, NotNull: true},
 					{Name: "assigned_at", Definition: "TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP"},
 				},
+				Indexes: []dberd.Index{
+					{Name: "user_roles_pkey", Columns: []string{"user_id", "role_id"}, Type: "BTREE", IsUnique: true, IsPrimary: true},
+				},
 			},
 			{
 				Name: "public.posts",
 				Columns: []dberd.Column{
-					{Name: "id", Definition: "INTEGER NOT NULL DEFAULT nextval('posts_id_seq'::regclass)", IsPrimary: true},
-					{Name: "user_id", Definition: "INTEGER NOT NULL"},
-					{Name: "title", Definition: "CHARACTER VARYING NOT NULL"},
+					{Name: "id", Definition: "INTEGER NOT NULL DEFAULT nextval('posts_id_seq'::regclass)", IsPrimary: true, NotNull: true},
+					{Name: "user_id", Definition: "INTEGER NOT NULL", NotNull: true},
+					{Name: "title", Definition: "CHARACTER VARYING(255) NOT NULL", NotNull: true},
+					{
+						Name:       "title_length",
+						Definition: "INTEGER GENERATED ALWAYS AS (length((title)::text)) STORED",
+						Generated:  &dberd.GeneratedColumn{Expression: "length((title)::text)", Stored: true},
+					},
 					{Name: "content", Definition: "TEXT"},
 					{Name: "created_at", Definition: "TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP"},
 				},
+				Indexes: []dberd.Index{
+					{Name: "posts_pkey", Columns: []string{"id"}, Type: "BTREE", IsUnique: true, IsPrimary: true},
+				},
 			},
 			{
 				Name: "public.categories",
 				Columns: []dberd.Column{
-					{Name: "id", Definition: "INTEGER NOT NULL DEFAULT nextval('categories_id_seq'::regclass)", IsPrimary: true},
-					{Name: "name", Definition: "CHARACTER VARYING NOT NULL"},
+					{Name: "id", Definition: "INTEGER NOT NULL DEFAULT nextval('categories_id_seq'::regclass)", IsPrimary: true, NotNull: true},
+					{Name: "name", Definition: "CHARACTER VARYING(100) NOT NULL", NotNull: true},
 					{Name: "description", Definition: "TEXT"},
 					{Name: "parent_id", Definition: "INTEGER", Comment: "Self-referencing foreign key for category hierarchy"},
 					{Name: "created_at", Definition: "TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP"},
 				},
+				Indexes: []dberd.Index{
+					{Name: "categories_pkey", Columns: []string{"id"}, Type: "BTREE", IsUnique: true, IsPrimary: true},
+				},
 			},
 			{
 				Name: "public.post_categories",
 				Columns: []dberd.Column{
-					{Name: "post_id", Definition: "INTEGER NOT NULL", IsPrimary: true},
-					{Name: "category_id", Definition: "INTEGER NOT NULL", IsPrimary: true},
+					{Name: "post_id", Definition: "INTEGER NOT NULL", IsPrimary: true, NotNull: true},
+					{Name: "category_id", Definition: "INTEGER NOT NULL", IsPrimary: true, NotNull: true},
+				},
+				Indexes: []dberd.Index{
+					{Name: "post_categories_pkey", Columns: []string{"post_id", "category_id"}, Type: "BTREE", IsUnique: true, IsPrimary: true},
 				},
 			},
 			{
 				Name: "public.comments",
 				Columns: []dberd.Column{
-					{Name: "id", Definition: "INTEGER NOT NULL DEFAULT nextval('comments_id_seq'::regclass)", IsPrimary: true},
-					{Name: "post_id", Definition: "INTEGER NOT NULL"},
-					{Name: "user_id", Definition: "INTEGER NOT NULL"},
-					{Name: "content", Definition: "TEXT NOT NULL"},
+					{Name: "id", Definition: "INTEGER NOT NULL DEFAULT nextval('comments_id_seq'::regclass)", IsPrimary: true, NotNull: true},
+					{Name: "post_id", Definition: "INTEGER NOT NULL", NotNull: true},
+					{Name: "user_id", Definition: "INTEGER NOT NULL", NotNull: true},
+					{Name: "content", Definition: "TEXT NOT NULL", NotNull: true},
 					{Name: "created_at", Definition: "TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP"},
 				},
+				Indexes: []dberd.Index{
+					{Name: "comments_pkey", Columns: []string{"id"}, Type: "BTREE", IsUnique: true, IsPrimary: true},
+				},
+			},
+		},
+		References: []dberd.Reference{
+			{Source: dberd.TableColumn{Table: "public.categories", Column: "parent_id"}, Target: dberd.TableColumn{Table: "public.categories", Column: "id"}, Cardinality: dberd.CardinalityManyToOne, Optional: true},
+			{Source: dberd.TableColumn{Table: "public.comments", Column: "post_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+			{Source: dberd.TableColumn{Table: "public.comments", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+			{Source: dberd.TableColumn{Table: "public.post_categories", Column: "category_id"}, Target: dberd.TableColumn{Table: "public.categories", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+			{Source: dberd.TableColumn{Table: "public.post_categories", Column: "post_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+			{Source: dberd.TableColumn{Table: "public.posts", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "role_id"}, Target: dberd.TableColumn{Table: "public.roles", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+		},
+	}
+
+	expected.Sort()
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestExtractSchemaPartitions(t *testing.T) {
+	t.Parallel()
+
+	container, db := setupTestDB(t)
+	defer func() {
+		err := container.Terminate(context.Background())
+		if err != nil {
+			slog.Warn("terminating postgres container", "error", err)
+		}
+	}()
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE public.users (
+			id SERIAL PRIMARY KEY
+		);
+
+		CREATE TABLE public.events (
+			id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL REFERENCES public.users(id),
+			created_at DATE NOT NULL,
+			PRIMARY KEY (id, created_at)
+		) PARTITION BY RANGE (created_at);
+
+		CREATE TABLE public.events_2024_01 PARTITION OF public.events
+			FOR VALUES FROM ('2024-01-01') TO ('2024-02-01');
+
+		CREATE TABLE public.events_2024_02 PARTITION OF public.events
+			FOR VALUES FROM ('2024-02-01') TO ('2024-03-01');
+	`)
+	require.NoError(t, err)
+
+	expectedTables := []dberd.Table{
+		{
+			Name: "public.events",
+			Columns: []dberd.Column{
+				{Name: "id", Definition: "INTEGER NOT NULL", IsPrimary: true, NotNull: true},
+				{Name: "created_at", Definition: "DATE NOT NULL", IsPrimary: true, NotNull: true},
+				{Name: "user_id", Definition: "INTEGER NOT NULL", NotNull: true},
+			},
+			Indexes: []dberd.Index{
+				{Name: "events_pkey", Columns: []string{"id", "created_at"}, Type: "BTREE", IsUnique: true, IsPrimary: true},
+			},
+			Partitioning: &dberd.Partitioning{
+				Strategy: "RANGE",
+				Key:      "created_at",
+			},
+		},
+		{
+			Name: "public.users",
+			Columns: []dberd.Column{
+				{Name: "id", Definition: "INTEGER NOT NULL DEFAULT nextval('users_id_seq'::regclass)", IsPrimary: true, NotNull: true},
+			},
+			Indexes: []dberd.Index{
+				{Name: "users_pkey", Columns: []string{"id"}, Type: "BTREE", IsUnique: true, IsPrimary: true},
+			},
+		},
+	}
+
+	expectedReferences := []dberd.Reference{
+		{Source: dberd.TableColumn{Table: "public.events", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
+	}
+
+	t.Run("folds partitions", func(t *testing.T) {
+		actual, err := NewSourceFromDB(db).ExtractSchema(ctx)
+		require.NoError(t, err)
+
+		actual.Sort()
+		actual.Metadata = nil
+
+		expected := dberd.Schema{
+			Tables:     expectedTables,
+			References: expectedReferences,
+		}
+
+		expected.Sort()
+
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("lists partitions", func(t *testing.T) {
+		actual, err := NewSourceFromDB(db, WithPartitionList()).ExtractSchema(ctx)
+		require.NoError(t, err)
+
+		actual.Sort()
+
+		require.Len(t, actual.Tables, 2)
+		assert.Equal(t, &dberd.Partitioning{
+			Strategy: "RANGE",
+			Key:      "created_at",
+			Partitions: []dberd.Partition{
+				{Name: "public.events_2024_01", Bound: "FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')"},
+				{Name: "public.events_2024_02", Bound: "FOR VALUES FROM ('2024-02-01') TO ('2024-03-01')"},
+			},
+		}, actual.Tables[0].Partitioning)
+		assert.Equal(t, expectedReferences, actual.References)
+	})
+}
+
+func TestExtractSchemaRoutines(t *testing.T) {
+	t.Parallel()
+
+	container, db := setupTestDB(t)
+	defer func() {
+		err := container.Terminate(context.Background())
+		if err != nil {
+			slog.Warn("terminating postgres container", "error", err)
+		}
+	}()
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE public.posts (
+			id SERIAL PRIMARY KEY,
+			title VARCHAR(255) NOT NULL
+		);
+
+		CREATE TABLE public.posts_audit (
+			post_id INTEGER NOT NULL,
+			changed_at TIMESTAMP NOT NULL DEFAULT now()
+		);
+
+		CREATE FUNCTION public.audit_posts() RETURNS trigger AS $$
+		BEGIN
+			INSERT INTO public.posts_audit (post_id) VALUES (NEW.id);
+			RETURN NEW;
+		END;
+		$$ LANGUAGE plpgsql;
+
+		CREATE TRIGGER posts_audit
+			AFTER INSERT OR UPDATE ON public.posts
+			FOR EACH ROW EXECUTE FUNCTION public.audit_posts();
+	`)
+	require.NoError(t, err)
+
+	t.Run("skips routines by default", func(t *testing.T) {
+		actual, err := NewSourceFromDB(db).ExtractSchema(ctx)
+		require.NoError(t, err)
+
+		assert.Nil(t, actual.Routines)
+	})
+
+	t.Run("extracts routines", func(t *testing.T) {
+		actual, err := NewSourceFromDB(db, WithRoutines()).ExtractSchema(ctx)
+		require.NoError(t, err)
+
+		expected := &dberd.Routines{
+			Sequences: []dberd.Sequence{
+				{
+					Name:    "public.posts_id_seq",
+					Type:    "integer",
+					OwnedBy: &dberd.TableColumn{Table: "public.posts", Column: "id"},
+				},
+			},
+			Functions: []dberd.Function{
+				{Name: "public.audit_posts", Returns: "trigger", Language: "plpgsql"},
+			},
+			Triggers: []dberd.Trigger{
+				{
+					Name:     "posts_audit",
+					Table:    "public.posts",
+					Function: "public.audit_posts()",
+					Timing:   "AFTER",
+					Events:   []string{"INSERT", "UPDATE"},
+					ForEach:  "ROW",
+				},
+			},
+		}
+
+		assert.Equal(t, expected, actual.Routines)
+	})
+}
+
+func TestTriggerRowsToSchemaTriggers(t *testing.T) {
+	t.Parallel()
+
+	triggerRows := []triggerRow{
+		{
+			tableSchema:       "public",
+			tableName:         "posts",
+			triggerName:       "posts_audit",
+			functionSchema:    "public",
+			functionName:      "audit_posts",
+			functionArguments: "text",
+			triggerType:       triggerTypeRow | triggerTypeInsert | triggerTypeUpdate,
+		},
+		{
+			tableSchema:    "public",
+			tableName:      "users",
+			triggerName:    "users_touch",
+			functionSchema: "audit",
+			functionName:   "touch",
+			triggerType:    triggerTypeRow | triggerTypeBefore | triggerTypeUpdate,
+		},
+		{
+			tableSchema:    "public",
+			tableName:      "users_view",
+			triggerName:    "users_view_write",
+			functionSchema: "public",
+			functionName:   "write_users",
+			triggerType:    triggerTypeRow | triggerTypeInstead | triggerTypeInsert | triggerTypeDelete,
+		},
+		{
+			tableSchema:    "public",
+			tableName:      "logs",
+			triggerName:    "logs_truncate",
+			functionSchema: "public",
+			functionName:   "on_truncate",
+			triggerType:    triggerTypeTruncate,
+		},
+	}
+
+	expected := []dberd.Trigger{
+		{Name: "posts_audit", Table: "public.posts", Function: "public.audit_posts(text)", Timing: "AFTER", Events: []string{"INSERT", "UPDATE"}, ForEach: "ROW"},
+		{Name: "users_touch", Table: "public.users", Function: "audit.touch()", Timing: "BEFORE", Events: []string{"UPDATE"}, ForEach: "ROW"},
+		{Name: "users_view_write", Table: "public.users_view", Function: "public.write_users()", Timing: "INSTEAD OF", Events: []string{"INSERT", "DELETE"}, ForEach: "ROW"},
+		{Name: "logs_truncate", Table: "public.logs", Function: "public.on_truncate()", Timing: "AFTER", Events: []string{"TRUNCATE"}, ForEach: "STATEMENT"},
+	}
+
+	assert.Equal(t, expected, triggerRowsToSchemaTriggers(triggerRows))
+}
+
+func TestCheckServerVersion(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, checkServerVersion(120000, "12.0"))
+	require.NoError(t, checkServerVersion(160002, "16.2 (Debian 16.2-1.pgdg120+2)"))
+	require.EqualError(t, checkServerVersion(110022, "11.22"), "unsupported server version 11.22, PostgreSQL 12 or later is required")
+}
+
+func TestExtractSchemaSecurity(t *testing.T) {
+	t.Parallel()
+
+	container, db := setupTestDB(t)
+	defer func() {
+		err := container.Terminate(context.Background())
+		if err != nil {
+			slog.Warn("terminating postgres container", "error", err)
+		}
+	}()
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, `
+		CREATE ROLE app_user;
+		CREATE ROLE "app,admin";
+
+		CREATE TABLE public.documents (
+			id INTEGER PRIMARY KEY,
+			owner TEXT NOT NULL
+		);
+
+		ALTER TABLE public.documents ENABLE ROW LEVEL SECURITY;
+
+		CREATE POLICY documents_owner ON public.documents
+			FOR SELECT TO app_user, "app,admin"
+			USING (owner = current_user);
+
+		GRANT SELECT, INSERT ON public.documents TO app_user;
+	`)
+	require.NoError(t, err)
+
+	actual, err := NewSourceFromDB(db, WithSecurity()).ExtractSchema(ctx)
+	require.NoError(t, err)
+
+	actual.Sort()
+
+	require.Len(t, actual.Tables, 1)
+	assert.True(t, actual.Tables[0].RowSecurity)
+	assert.Equal(t, []dberd.Policy{
+		{
+			Name:       "documents_owner",
+			Command:    "SELECT",
+			Roles:      []string{"app,admin", "app_user"},
+			Using:      "(owner = CURRENT_USER)",
+			Permissive: true,
+		},
+	}, actual.Tables[0].Policies)
+
+	assert.Contains(t, actual.Grants, dberd.Grant{
+		Role:       "app_user",
+		Table:      "public.documents",
+		Privileges: []string{"INSERT", "SELECT"},
+	})
+}
+
+func TestGrantRowsToSchemaGrants(t *testing.T) {
+	t.Parallel()
+
+	grantRows := []grantRow{
+		{grantee: "app_user", tableSchema: "public", tableName: "posts", privilegeType: "INSERT"},
+		{grantee: "app_user", tableSchema: "public", tableName: "posts", privilegeType: "SELECT"},
+		{grantee: "reporting", tableSchema: "public", tableName: "posts", privilegeType: "SELECT"},
+		{grantee: "app_user", tableSchema: "public", tableName: "users", privilegeType: "SELECT"},
+	}
+
+	expected := []dberd.Grant{
+		{Role: "app_user", Table: "public.posts", Privileges: []string{"INSERT", "SELECT"}},
+		{Role: "reporting", Table: "public.posts", Privileges: []string{"SELECT"}},
+		{Role: "app_user", Table: "public.users", Privileges: []string{"SELECT"}},
+	}
+
+	assert.Equal(t, expected, grantRowsToSchemaGrants(grantRows))
+}
+
+func TestExtractSchemaInheritanceAndForeignTables(t *testing.T) {
+	t.Parallel()
+
+	container, db := setupTestDB(t)
+	defer func() {
+		err := container.Terminate(context.Background())
+		if err != nil {
+			slog.Warn("terminating postgres container", "error", err)
+		}
+	}()
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE public.cities (
+			name TEXT NOT NULL,
+			population INTEGER
+		);
+
+		CREATE TABLE public.capitals (
+			country TEXT NOT NULL
+		) INHERITS (public.cities);
+
+		CREATE EXTENSION postgres_fdw;
+
+		CREATE SERVER legacy FOREIGN DATA WRAPPER postgres_fdw
+			OPTIONS (host 'legacy.internal', dbname 'legacy');
+
+		CREATE FOREIGN TABLE public.legacy_users (
+			id INTEGER NOT NULL,
+			login TEXT
+		) SERVER legacy;
+	`)
+	require.NoError(t, err)
+
+	actual, err := NewSourceFromDB(db).ExtractSchema(ctx)
+	require.NoError(t, err)
+
+	actual.Sort()
+	actual.Metadata = nil
+
+	expected := dberd.Schema{
+		Tables: []dberd.Table{
+			{
+				Name: "public.capitals",
+				Columns: []dberd.Column{
+					{Name: "name", Definition: "TEXT NOT NULL", NotNull: true},
+					{Name: "population", Definition: "INTEGER"},
+					{Name: "country", Definition: "TEXT NOT NULL", NotNull: true},
+				},
+			},
+			{
+				Name: "public.cities",
+				Columns: []dberd.Column{
+					{Name: "name", Definition: "TEXT NOT NULL", NotNull: true},
+					{Name: "population", Definition: "INTEGER"},
+				},
+			},
+			{
+				Name: "public.legacy_users",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INTEGER NOT NULL", NotNull: true},
+					{Name: "login", Definition: "TEXT"},
+				},
+				ForeignServer: "legacy",
 			},
 		},
 		References: []dberd.Reference{
-			{Source: dberd.TableColumn{Table: "public.categories", Column: "parent_id"}, Target: dberd.TableColumn{Table: "public.categories", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.comments", Column: "post_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.comments", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.post_categories", Column: "category_id"}, Target: dberd.TableColumn{Table: "public.categories", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.post_categories", Column: "post_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.posts", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "role_id"}, Target: dberd.TableColumn{Table: "public.roles", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}},
+			{
+				Source: dberd.TableColumn{Table: "public.capitals"},
+				Target: dberd.TableColumn{Table: "public.cities"},
+				Kind:   dberd.ReferenceKindInheritance,
+			},
 		},
 	}
 
@@ -188,6 +616,75 @@ func TestExtractSchema(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestExtractSchemaStats(t *testing.T) {
+	t.Parallel()
+
+	container, db := setupTestDB(t)
+	defer func() {
+		err := container.Terminate(context.Background())
+		if err != nil {
+			slog.Warn("terminating postgres container", "error", err)
+		}
+	}()
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE public.users (
+			id INTEGER PRIMARY KEY
+		);
+
+		CREATE TABLE public.events (
+			id INTEGER NOT NULL,
+			created_at DATE NOT NULL
+		) PARTITION BY RANGE (created_at);
+
+		CREATE TABLE public.events_2024_01 PARTITION OF public.events
+			FOR VALUES FROM ('2024-01-01') TO ('2024-02-01');
+
+		CREATE TABLE public.events_2024_02 PARTITION OF public.events
+			FOR VALUES FROM ('2024-02-01') TO ('2024-03-01');
+
+		INSERT INTO public.users SELECT generate_series(1, 1000);
+
+		INSERT INTO public.events
+		SELECT i, DATE '2024-01-01' + (i % 50)
+		FROM generate_series(1, 500) AS i;
+
+		ANALYZE;
+	`)
+	require.NoError(t, err)
+
+	t.Run("skips stats by default", func(t *testing.T) {
+		actual, err := NewSourceFromDB(db).ExtractSchema(ctx)
+		require.NoError(t, err)
+
+		for _, table := range actual.Tables {
+			assert.Nil(t, table.Stats, table.Name)
+		}
+	})
+
+	t.Run("extracts stats", func(t *testing.T) {
+		actual, err := NewSourceFromDB(db, WithStats()).ExtractSchema(ctx)
+		require.NoError(t, err)
+
+		actual.Sort()
+
+		require.Len(t, actual.Tables, 2)
+
+		events := actual.Tables[0]
+		require.NotNil(t, events.Stats)
+		assert.Equal(t, int64(500), events.Stats.Rows)
+		assert.Positive(t, events.Stats.SizeBytes)
+
+		users := actual.Tables[1]
+		require.NotNil(t, users.Stats)
+		assert.Equal(t, int64(1000), users.Stats.Rows)
+		assert.Positive(t, users.Stats.SizeBytes)
+	})
+}
+
 func setupTestDB(t *testing.T) (testcontainers.Container, *sql.DB) {
 	ctx := context.Background()
 
@@ -0,0 +1,311 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/holydocs/dberd"
+)
+
+// extractRoutinesSection extracts sequences, functions and triggers of the database.
+func (s *Source) extractRoutinesSection(ctx context.Context) (*dberd.Routines, error) {
+	var (
+		routines dberd.Routines
+		err      error
+	)
+
+	routines.Sequences, err = s.extractSequences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("extracting sequences: %w", err)
+	}
+
+	routines.Functions, err = s.extractFunctions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("extracting functions: %w", err)
+	}
+
+	routines.Triggers, err = s.extractTriggers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("extracting triggers: %w", err)
+	}
+
+	return &routines, nil
+}
+
+const extractSequencesQuery = `
+	SELECT
+		sn.nspname AS sequence_schema,
+		s.relname AS sequence_name,
+		format_type(seq.seqtypid, NULL) AS data_type,
+		tn.nspname AS table_schema,
+		t.relname AS table_name,
+		a.attname AS column_name
+	FROM pg_catalog.pg_class s
+	JOIN pg_catalog.pg_namespace sn ON sn.oid = s.relnamespace
+	JOIN pg_catalog.pg_sequence seq ON seq.seqrelid = s.oid
+	LEFT JOIN pg_catalog.pg_depend d
+		ON d.objid = s.oid
+		AND d.classid = 'pg_catalog.pg_class'::regclass
+		AND d.refclassid = 'pg_catalog.pg_class'::regclass
+		AND d.deptype IN ('a', 'i')
+	LEFT JOIN pg_catalog.pg_class t ON t.oid = d.refobjid
+	LEFT JOIN pg_catalog.pg_namespace tn ON tn.oid = t.relnamespace
+	LEFT JOIN pg_catalog.pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+	WHERE s.relkind = 'S'
+	AND sn.nspname NOT IN ('pg_catalog', 'information_schema')
+	ORDER BY sn.nspname, s.relname;`
+
+type sequenceRow struct {
+	sequenceSchema string
+	sequenceName   string
+	dataType       string
+	tableSchema    *string
+	tableName      *string
+	columnName     *string
+}
+
+// extractSequences queries the database for sequences and the columns owning them.
+func (s *Source) extractSequences(ctx context.Context) ([]dberd.Sequence, error) {
+	rows, err := s.db.QueryContext(ctx, extractSequencesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("querying sequences: %w", err)
+	}
+	defer rows.Close()
+
+	var sequenceRows []sequenceRow
+
+	for rows.Next() {
+		var r sequenceRow
+		if err := rows.Scan(
+			&r.sequenceSchema,
+			&r.sequenceName,
+			&r.dataType,
+			&r.tableSchema,
+			&r.tableName,
+			&r.columnName,
+		); err != nil {
+			return nil, fmt.Errorf("scanning sequences row: %w", err)
+		}
+
+		sequenceRows = append(sequenceRows, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sequences rows error: %w", err)
+	}
+
+	return sequenceRowsToSchemaSequences(sequenceRows), nil
+}
+
+// sequenceRowsToSchemaSequences converts a slice of sequenceRow into a slice of dberd.Sequence.
+func sequenceRowsToSchemaSequences(sequenceRows []sequenceRow) []dberd.Sequence {
+	sequences := make([]dberd.Sequence, 0, len(sequenceRows))
+
+	for _, row := range sequenceRows {
+		sequence := dberd.Sequence{
+			Name: row.sequenceSchema + "." + row.sequenceName,
+			Type: row.dataType,
+		}
+
+		if row.tableSchema != nil && row.tableName != nil && row.columnName != nil {
+			sequence.OwnedBy = &dberd.TableColumn{
+				Table:  *row.tableSchema + "." + *row.tableName,
+				Column: *row.columnName,
+			}
+		}
+
+		sequences = append(sequences, sequence)
+	}
+
+	return sequences
+}
+
+const extractFunctionsQuery = `
+	SELECT
+		n.nspname AS function_schema,
+		p.proname AS function_name,
+		pg_get_function_identity_arguments(p.oid) AS arguments,
+		pg_get_function_result(p.oid) AS result,
+		l.lanname AS language
+	FROM pg_catalog.pg_proc p
+	JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
+	JOIN pg_catalog.pg_language l ON l.oid = p.prolang
+	WHERE p.prokind IN ('f', 'p')
+	AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+	AND NOT EXISTS (
+		SELECT 1
+		FROM pg_catalog.pg_depend d
+		WHERE d.objid = p.oid
+		AND d.deptype = 'e'
+	)
+	ORDER BY n.nspname, p.proname, arguments;`
+
+type functionRow struct {
+	functionSchema string
+	functionName   string
+	arguments      string
+	result         *string
+	language       string
+}
+
+// extractFunctions queries the database for user-defined functions and procedures.
+// Functions installed by extensions are skipped.
+func (s *Source) extractFunctions(ctx context.Context) ([]dberd.Function, error) {
+	rows, err := s.db.QueryContext(ctx, extractFunctionsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("querying functions: %w", err)
+	}
+	defer rows.Close()
+
+	var functions []dberd.Function
+
+	for rows.Next() {
+		var r functionRow
+		if err := rows.Scan(
+			&r.functionSchema,
+			&r.functionName,
+			&r.arguments,
+			&r.result,
+			&r.language,
+		); err != nil {
+			return nil, fmt.Errorf("scanning functions row: %w", err)
+		}
+
+		function := dberd.Function{
+			Name:      r.functionSchema + "." + r.functionName,
+			Arguments: r.arguments,
+			Language:  r.language,
+		}
+
+		if r.result != nil {
+			function.Returns = *r.result
+		}
+
+		functions = append(functions, function)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("functions rows error: %w", err)
+	}
+
+	return functions, nil
+}
+
+const extractTriggersQuery = `
+	SELECT
+		tn.nspname AS table_schema,
+		t.relname AS table_name,
+		tg.tgname AS trigger_name,
+		pn.nspname AS function_schema,
+		p.proname AS function_name,
+		pg_get_function_identity_arguments(p.oid) AS function_arguments,
+		tg.tgtype::int AS trigger_type
+	FROM pg_catalog.pg_trigger tg
+	JOIN pg_catalog.pg_class t ON t.oid = tg.tgrelid
+	JOIN pg_catalog.pg_namespace tn ON tn.oid = t.relnamespace
+	JOIN pg_catalog.pg_proc p ON p.oid = tg.tgfoid
+	JOIN pg_catalog.pg_namespace pn ON pn.oid = p.pronamespace
+	WHERE NOT tg.tgisinternal
+	AND NOT t.relispartition
+	AND tn.nspname NOT IN ('pg_catalog', 'information_schema')
+	ORDER BY tn.nspname, t.relname, tg.tgname;`
+
+// Trigger type bits of pg_trigger.tgtype.
+const (
+	triggerTypeRow      = 1 << 0
+	triggerTypeBefore   = 1 << 1
+	triggerTypeInsert   = 1 << 2
+	triggerTypeDelete   = 1 << 3
+	triggerTypeUpdate   = 1 << 4
+	triggerTypeTruncate = 1 << 5
+	triggerTypeInstead  = 1 << 6
+)
+
+type triggerRow struct {
+	tableSchema       string
+	tableName         string
+	triggerName       string
+	functionSchema    string
+	functionName      string
+	functionArguments string
+	triggerType       int
+}
+
+// extractTriggers queries the database for user-defined table triggers.
+func (s *Source) extractTriggers(ctx context.Context) ([]dberd.Trigger, error) {
+	rows, err := s.db.QueryContext(ctx, extractTriggersQuery)
+	if err != nil {
+		return nil, fmt.Errorf("querying triggers: %w", err)
+	}
+	defer rows.Close()
+
+	var triggerRows []triggerRow
+
+	for rows.Next() {
+		var r triggerRow
+		if err := rows.Scan(
+			&r.tableSchema,
+			&r.tableName,
+			&r.triggerName,
+			&r.functionSchema,
+			&r.functionName,
+			&r.functionArguments,
+			&r.triggerType,
+		); err != nil {
+			return nil, fmt.Errorf("scanning triggers row: %w", err)
+		}
+
+		triggerRows = append(triggerRows, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("triggers rows error: %w", err)
+	}
+
+	return triggerRowsToSchemaTriggers(triggerRows), nil
+}
+
+// triggerRowsToSchemaTriggers converts a slice of triggerRow into a slice of dberd.Trigger,
+// decoding timing, events and level from the trigger type bits.
+func triggerRowsToSchemaTriggers(triggerRows []triggerRow) []dberd.Trigger {
+	triggers := make([]dberd.Trigger, 0, len(triggerRows))
+
+	for _, row := range triggerRows {
+		trigger := dberd.Trigger{
+			Name:     row.triggerName,
+			Table:    row.tableSchema + "." + row.tableName,
+			Function: row.functionSchema + "." + row.functionName + "(" + row.functionArguments + ")",
+			Timing:   "AFTER",
+			ForEach:  "STATEMENT",
+		}
+
+		switch {
+		case row.triggerType&triggerTypeInstead != 0:
+			trigger.Timing = "INSTEAD OF"
+		case row.triggerType&triggerTypeBefore != 0:
+			trigger.Timing = "BEFORE"
+		}
+
+		if row.triggerType&triggerTypeRow != 0 {
+			trigger.ForEach = "ROW"
+		}
+
+		for _, event := range []struct {
+			bit  int
+			name string
+		}{
+			{bit: triggerTypeInsert, name: "INSERT"},
+			{bit: triggerTypeUpdate, name: "UPDATE"},
+			{bit: triggerTypeDelete, name: "DELETE"},
+			{bit: triggerTypeTruncate, name: "TRUNCATE"},
+		} {
+			if row.triggerType&event.bit != 0 {
+				trigger.Events = append(trigger.Events, event.name)
+			}
+		}
+
+		triggers = append(triggers, trigger)
+	}
+
+	return triggers
+}
@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/holydocs/dberd"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const extractRowSecurityQuery = `
+	SELECT
+		n.nspname AS table_schema,
+		c.relname AS table_name,
+		c.relrowsecurity AS row_security,
+		p.policyname AS policy_name,
+		p.permissive AS permissive,
+		p.roles::text[] AS roles,
+		p.cmd AS command,
+		p.qual AS using_expression,
+		p.with_check AS check_expression
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_catalog.pg_policies p ON p.schemaname = n.nspname AND p.tablename = c.relname
+	WHERE c.relkind IN ('r', 'p')
+	AND (c.relrowsecurity OR p.policyname IS NOT NULL)
+	AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+	ORDER BY n.nspname, c.relname, p.policyname;`
+
+type rowSecurityRow struct {
+	tableSchema     string
+	tableName       string
+	rowSecurity     bool
+	policyName      *string
+	permissive      *string
+	roles           []string
+	command         *string
+	usingExpression *string
+	checkExpression *string
+}
+
+// extractRowSecurity queries the database for tables with row-level security
+// and their policies, and applies them to the given tables.
+func (s *Source) extractRowSecurity(ctx context.Context, tables []dberd.Table) error {
+	rows, err := s.db.QueryContext(ctx, extractRowSecurityQuery)
+	if err != nil {
+		return fmt.Errorf("querying row security: %w", err)
+	}
+	defer rows.Close()
+
+	var rowSecurityRows []rowSecurityRow
+
+	// Roles are scanned as an array, as quoted role names can contain commas.
+	types := pgtype.NewMap()
+
+	for rows.Next() {
+		var r rowSecurityRow
+		if err := rows.Scan(
+			&r.tableSchema,
+			&r.tableName,
+			&r.rowSecurity,
+			&r.policyName,
+			&r.permissive,
+			types.SQLScanner(&r.roles),
+			&r.command,
+			&r.usingExpression,
+			&r.checkExpression,
+		); err != nil {
+			return fmt.Errorf("scanning row security row: %w", err)
+		}
+
+		rowSecurityRows = append(rowSecurityRows, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row security rows error: %w", err)
+	}
+
+	applyRowSecurityRows(tables, rowSecurityRows)
+
+	return nil
+}
+
+// applyRowSecurityRows sets row-level security flags and policies on the matching tables.
+func applyRowSecurityRows(tables []dberd.Table, rowSecurityRows []rowSecurityRow) {
+	tableIndex := make(map[string]int, len(tables))
+	for i := range tables {
+		tableIndex[tables[i].Name] = i
+	}
+
+	for _, row := range rowSecurityRows {
+		i, ok := tableIndex[row.tableSchema+"."+row.tableName]
+		if !ok {
+			continue
+		}
+
+		tables[i].RowSecurity = row.rowSecurity
+
+		if row.policyName == nil {
+			continue
+		}
+
+		policy := dberd.Policy{
+			Name:       *row.policyName,
+			Permissive: row.permissive == nil || *row.permissive == "PERMISSIVE",
+		}
+
+		if row.command != nil {
+			policy.Command = *row.command
+		}
+
+		if len(row.roles) > 0 {
+			policy.Roles = row.roles
+		}
+
+		if row.usingExpression != nil {
+			policy.Using = *row.usingExpression
+		}
+
+		if row.checkExpression != nil {
+			policy.Check = *row.checkExpression
+		}
+
+		tables[i].Policies = append(tables[i].Policies, policy)
+	}
+}
+
+const extractGrantsQuery = `
+	SELECT
+		grantee,
+		table_schema,
+		table_name,
+		privilege_type
+	FROM information_schema.role_table_grants
+	WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+	ORDER BY table_schema, table_name, grantee, privilege_type;`
+
+type grantRow struct {
+	grantee       string
+	tableSchema   string
+	tableName     string
+	privilegeType string
+}
+
+// extractGrants queries the database for table privileges granted to roles.
+func (s *Source) extractGrants(ctx context.Context) ([]dberd.Grant, error) {
+	rows, err := s.db.QueryContext(ctx, extractGrantsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("querying grants: %w", err)
+	}
+	defer rows.Close()
+
+	var grantRows []grantRow
+
+	for rows.Next() {
+		var r grantRow
+		if err := rows.Scan(
+			&r.grantee,
+			&r.tableSchema,
+			&r.tableName,
+			&r.privilegeType,
+		); err != nil {
+			return nil, fmt.Errorf("scanning grants row: %w", err)
+		}
+
+		grantRows = append(grantRows, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("grants rows error: %w", err)
+	}
+
+	return grantRowsToSchemaGrants(grantRows), nil
+}
+
+// grantRowsToSchemaGrants converts a slice of grantRow into a slice of dberd.Grant,
+// grouping privileges by role and table.
+func grantRowsToSchemaGrants(grantRows []grantRow) []dberd.Grant {
+	type grantKey struct {
+		role  string
+		table string
+	}
+
+	grantIndex := make(map[grantKey]int)
+
+	var grants []dberd.Grant
+
+	for _, row := range grantRows {
+		key := grantKey{role: row.grantee, table: row.tableSchema + "." + row.tableName}
+
+		i, ok := grantIndex[key]
+		if !ok {
+			i = len(grants)
+			grantIndex[key] = i
+			grants = append(grants, dberd.Grant{
+				Role:  key.role,
+				Table: key.table,
+			})
+		}
+
+		grants[i].Privileges = append(grants[i].Privileges, row.privilegeType)
+	}
+
+	return grants
+}
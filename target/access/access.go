@@ -0,0 +1,196 @@
+// Package access provides functionality for formatting database schemas as a role/table access matrix.
+// The matrix is a Markdown report of table privileges granted to roles, row-level security
+// flags and policies, which is meant to be reviewed next to the ERD.
+package access
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/holydocs/dberd"
+)
+
+// targetType defines the schema format type for access matrices.
+const targetType = dberd.TargetType("access")
+
+//go:embed schema.tmpl
+var templateFS embed.FS
+
+// Ensure Target implements dberd interfaces.
+var _ dberd.Target = (*Target)(nil)
+
+func init() {
+	dberd.RegisterTarget(dberd.TargetRegistration{
+		Name:          string(targetType),
+		Description:   "Markdown role/table access matrix with row-level security policies",
+		SourceOptions: dberd.Options{"security": "true"},
+		New: func(_ dberd.Options) (dberd.Target, error) {
+			return NewTarget()
+		},
+	})
+}
+
+// Target represents an access matrix formatter that converts database schema grants
+// and row-level security policies into a Markdown report.
+type Target struct {
+	template *template.Template
+}
+
+// matrix is the data passed to the schema template.
+type matrix struct {
+	Roles  []string
+	Rows   []matrixRow
+	Tables []dberd.Table
+}
+
+// matrixRow represents privileges of every matrix role on a single table.
+type matrixRow struct {
+	Table       string
+	RowSecurity string
+	Privileges  []string
+}
+
+// NewTarget creates a new access matrix formatter instance.
+func NewTarget() (*Target, error) {
+	tmpl, err := template.New("schema.tmpl").
+		Funcs(template.FuncMap{
+			"join": strings.Join,
+			"cell": cell,
+		}).
+		ParseFS(templateFS, "schema.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+
+	return &Target{
+		template: tmpl,
+	}, nil
+}
+
+// Capabilities returns target capabilities.
+func (t *Target) Capabilities() dberd.TargetCapabilities {
+	return dberd.TargetCapabilities{
+		Format: true,
+		Render: false,
+	}
+}
+
+// FormatSchema converts database schema grants and policies into a Markdown access matrix.
+func (t *Target) FormatSchema(_ context.Context, s dberd.Schema) (dberd.FormattedSchema, error) {
+	fs := dberd.FormattedSchema{
+		Type: targetType,
+	}
+
+	var buf bytes.Buffer
+
+	err := t.template.Execute(&buf, newMatrix(s))
+	if err != nil {
+		return dberd.FormattedSchema{}, fmt.Errorf("executing template: %w", err)
+	}
+
+	fs.Data = buf.Bytes()
+
+	return fs, nil
+}
+
+// RenderSchema is unsupported for access target.
+func (t *Target) RenderSchema(_ context.Context, _ dberd.FormattedSchema) ([]byte, error) {
+	return nil, errors.New("unsupported")
+}
+
+// newMatrix builds the access matrix with a row per table and a column per role.
+// Tables known only from grants are included as well.
+func newMatrix(s dberd.Schema) matrix {
+	var (
+		roleSet    = make(map[string]struct{})
+		tableIndex = make(map[string]int)
+		m          = matrix{}
+	)
+
+	for _, grant := range s.Grants {
+		roleSet[grant.Role] = struct{}{}
+	}
+
+	for role := range roleSet {
+		m.Roles = append(m.Roles, role)
+	}
+
+	sort.Strings(m.Roles)
+
+	roleIndex := make(map[string]int, len(m.Roles))
+	for i, role := range m.Roles {
+		roleIndex[role] = i
+	}
+
+	addRow := func(table string) int {
+		i, ok := tableIndex[table]
+		if !ok {
+			i = len(m.Rows)
+			tableIndex[table] = i
+			m.Rows = append(m.Rows, matrixRow{
+				Table:       table,
+				RowSecurity: "-",
+				Privileges:  make([]string, len(m.Roles)),
+			})
+		}
+		return i
+	}
+
+	for _, table := range s.Tables {
+		i := addRow(table.Name)
+
+		if table.RowSecurity || len(table.Policies) > 0 {
+			m.Rows[i].RowSecurity = rowSecurity(table)
+			m.Tables = append(m.Tables, table)
+		}
+	}
+
+	for _, grant := range s.Grants {
+		i := addRow(grant.Table)
+		m.Rows[i].Privileges[roleIndex[grant.Role]] = strings.Join(grant.Privileges, ", ")
+	}
+
+	sort.Slice(m.Rows, func(i, j int) bool {
+		return m.Rows[i].Table < m.Rows[j].Table
+	})
+
+	sort.Slice(m.Tables, func(i, j int) bool {
+		return m.Tables[i].Name < m.Tables[j].Name
+	})
+
+	return m
+}
+
+// rowSecurity returns a short description of the table row-level security state.
+func rowSecurity(table dberd.Table) string {
+	state := "disabled"
+	if table.RowSecurity {
+		state = "enabled"
+	}
+
+	switch len(table.Policies) {
+	case 0:
+		return state
+	case 1:
+		return state + " (1 policy)"
+	default:
+		return fmt.Sprintf("%s (%d policies)", state, len(table.Policies))
+	}
+}
+
+// cell escapes a value for a Markdown table cell.
+func cell(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	value = strings.ReplaceAll(value, "|", `\|`)
+
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,76 @@
+package access
+
+import (
+	"context"
+	_ "embed"
+	"testing"
+
+	"github.com/holydocs/dberd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	//go:embed testdata/schema.md
+	testSchema []byte
+)
+
+func TestFormatSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := dberd.Schema{
+		Tables: []dberd.Table{
+			{
+				Name: "public.users",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INTEGER NOT NULL", IsPrimary: true},
+				},
+			},
+			{
+				Name: "public.posts",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INTEGER NOT NULL", IsPrimary: true},
+					{Name: "owner", Definition: "TEXT NOT NULL"},
+				},
+				RowSecurity: true,
+				Policies: []dberd.Policy{
+					{
+						Name:       "posts_owner",
+						Command:    "ALL",
+						Roles:      []string{"app_user"},
+						Using:      "(owner = CURRENT_USER)",
+						Check:      "(owner = CURRENT_USER)",
+						Permissive: true,
+					},
+					{
+						Name:    "posts_not_archived",
+						Command: "SELECT",
+						Roles:   []string{"public"},
+						Using:   "(NOT archived)",
+					},
+				},
+			},
+		},
+		Grants: []dberd.Grant{
+			{Role: "app_user", Table: "public.posts", Privileges: []string{"SELECT", "INSERT", "UPDATE"}},
+			{Role: "app_user", Table: "public.users", Privileges: []string{"SELECT"}},
+			{Role: "reporting", Table: "public.users", Privileges: []string{"SELECT"}},
+			{Role: "reporting", Table: "public.audit_log", Privileges: []string{"SELECT"}},
+		},
+	}
+
+	ctx := context.Background()
+
+	target, err := NewTarget()
+	require.NoError(t, err)
+
+	actual, err := target.FormatSchema(ctx, schema)
+	require.NoError(t, err)
+
+	expected := dberd.FormattedSchema{
+		Type: targetType,
+		Data: testSchema,
+	}
+
+	assert.Equal(t, string(expected.Data), string(actual.Data))
+}
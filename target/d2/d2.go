@@ -8,6 +8,7 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/holydocs/dberd"
@@ -32,14 +33,64 @@ var (
 	_ dberd.Target = (*Target)(nil)
 )
 
+func init() {
+	dberd.RegisterTarget(dberd.TargetRegistration{
+		Name:        string(targetType),
+		Description: "D2 diagram, renders to SVG",
+		Options: []dberd.OptionSpec{
+			{Name: "routines", Description: "Render sequences, functions and triggers as side-notes", Default: "false"},
+			{Name: "size-scaling", Description: "Scale table border width by table size", Default: "false"},
+		},
+		New: newRegisteredTarget,
+	})
+}
+
+// newRegisteredTarget creates a Target from registry options.
+func newRegisteredTarget(opts dberd.Options) (dberd.Target, error) {
+	routines, err := opts.Bool("routines")
+	if err != nil {
+		return nil, err
+	}
+
+	sizeScaling, err := opts.Bool("size-scaling")
+	if err != nil {
+		return nil, err
+	}
+
+	var targetOpts []TargetOpt
+	if routines {
+		targetOpts = append(targetOpts, WithRoutines())
+	}
+
+	if sizeScaling {
+		targetOpts = append(targetOpts, WithSizeScaling())
+	}
+
+	t, err := NewTarget(targetOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
 // Target represents a D2 diagram formatter that converts database schemas into D2 format.
 // It handles the conversion of database schemas to D2 diagrams and their subsequent rendering.
 // The formatter uses an embedded template for diagram generation and supports customization
 // through various options for rendering and compilation.
 type Target struct {
-	template    *template.Template
-	renderOpts  *d2svg.RenderOpts
-	compileOpts *d2lib.CompileOptions
+	template     *template.Template
+	renderOpts   *d2svg.RenderOpts
+	compileOpts  *d2lib.CompileOptions
+	showRoutines bool
+	scaleBySize  bool
+}
+
+// templateData is the data passed to the schema template.
+type templateData struct {
+	dberd.Schema
+	ShowRoutines bool
+	ScaleBySize  bool
 }
 
 // TargetOpt is a function type that allows customization of a Target instance.
@@ -62,12 +113,35 @@ func WithCompileOpts(compileOpts *d2lib.CompileOptions) TargetOpt {
 	}
 }
 
+// WithRoutines returns a TargetOpt that renders the schema routines section as side-notes:
+// functions and sequences as pages, triggers as dashed edges from tables to their functions
+// and sequences linked to their owning columns.
+func WithRoutines() TargetOpt {
+	return func(t *Target) {
+		t.showRoutines = true
+	}
+}
+
+// WithSizeScaling returns a TargetOpt that scales table border width by table size,
+// so the largest tables stand out. Tables without statistics keep the default style.
+func WithSizeScaling() TargetOpt {
+	return func(t *Target) {
+		t.scaleBySize = true
+	}
+}
+
 // NewTarget creates a new D2 diagram formatter instance.
 // It initializes the template from the embedded schema.tmpl file and sets up default
 // rendering and compilation options. The formatter uses the ELK layout engine for
 // diagram arrangement.
-func NewTarget() (*Target, error) {
-	tmpl, err := template.ParseFS(templateFS, "schema.tmpl")
+func NewTarget(opts ...TargetOpt) (*Target, error) {
+	tmpl, err := template.New("schema.tmpl").
+		Funcs(template.FuncMap{
+			"join":        strings.Join,
+			"strokeWidth": strokeWidth,
+			"arrowheads":  arrowheads,
+		}).
+		ParseFS(templateFS, "schema.tmpl")
 	if err != nil {
 		return nil, fmt.Errorf("parsing template: %w", err)
 	}
@@ -81,7 +155,7 @@ func NewTarget() (*Target, error) {
 		return d2elklayout.DefaultLayout, nil
 	}
 
-	return &Target{
+	t := &Target{
 		template: tmpl,
 		renderOpts: &d2svg.RenderOpts{
 			Pad:     go2.Pointer(int64(5)),
@@ -91,7 +165,13 @@ func NewTarget() (*Target, error) {
 			LayoutResolver: layoutResolver,
 			Ruler:          ruler,
 		},
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t, nil
 }
 
 // Capabilities returns target capabilities.
@@ -110,7 +190,11 @@ func (t *Target) FormatSchema(_ context.Context, s dberd.Schema) (dberd.Formatte
 
 	var buf bytes.Buffer
 
-	err := t.template.Execute(&buf, s)
+	err := t.template.Execute(&buf, templateData{
+		Schema:       s,
+		ShowRoutines: t.showRoutines,
+		ScaleBySize:  t.scaleBySize,
+	})
 	if err != nil {
 		return dberd.FormattedSchema{}, fmt.Errorf("executing template: %w", err)
 	}
@@ -140,3 +224,64 @@ func (t *Target) RenderSchema(ctx context.Context, s dberd.FormattedSchema) ([]b
 
 	return out, nil
 }
+
+// strokeWidth returns a table border width for the given table statistics.
+// Size on disk is preferred, row count is used when the size is unknown.
+func strokeWidth(stats dberd.TableStats) int {
+	const (
+		mib = 1 << 20
+		gib = 1 << 30
+		tib = 1 << 40
+	)
+
+	if stats.SizeBytes > 0 {
+		switch {
+		case stats.SizeBytes >= tib:
+			return 8
+		case stats.SizeBytes >= 100*gib:
+			return 6
+		case stats.SizeBytes >= gib:
+			return 4
+		case stats.SizeBytes >= 100*mib:
+			return 3
+		case stats.SizeBytes >= mib:
+			return 2
+		default:
+			return 1
+		}
+	}
+
+	switch {
+	case stats.Rows >= 1_000_000_000:
+		return 8
+	case stats.Rows >= 100_000_000:
+		return 6
+	case stats.Rows >= 10_000_000:
+		return 4
+	case stats.Rows >= 1_000_000:
+		return 3
+	case stats.Rows >= 100_000:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// arrowheadShapes are the crow's foot arrowhead shapes of relationship ends.
+var arrowheadShapes = map[dberd.Multiplicity]string{
+	dberd.MultiplicityZeroOrOne:  "cf-one",
+	dberd.MultiplicityExactlyOne: "cf-one-required",
+	dberd.MultiplicityZeroOrMany: "cf-many",
+}
+
+// arrowheads returns crow's foot arrowhead attributes for a reference with known cardinality.
+// An empty string is returned when the source did not compute cardinality.
+func arrowheads(ref dberd.Reference) string {
+	if ref.Cardinality == "" {
+		return ""
+	}
+
+	source, target := ref.Multiplicities()
+
+	return fmt.Sprintf("source-arrowhead.shape: %s; target-arrowhead.shape: %s", arrowheadShapes[source], arrowheadShapes[target])
+}
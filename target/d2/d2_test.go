@@ -4,6 +4,7 @@ import (
 	"context"
 	_ "embed"
 	"testing"
+	"time"
 
 	"github.com/holydocs/dberd"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +17,9 @@ var (
 
 	//go:embed testdata/schema.svg
 	testSVG []byte
+
+	//go:embed testdata/schema_extended.d2
+	testExtendedSchema []byte
 )
 
 func TestFormatSchema(t *testing.T) {
@@ -114,6 +118,135 @@ func TestFormatSchema(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestFormatSchemaExtended(t *testing.T) {
+	t.Parallel()
+
+	schema := dberd.Schema{
+		Tables: []dberd.Table{
+			{
+				Name: "public.users",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "name", Definition: "VARCHAR(255) NOT NULL"},
+				},
+				Locality: "GLOBAL",
+				Stats:    &dberd.TableStats{Rows: 1200000, SizeBytes: 350 << 20},
+			},
+			{
+				Name: "public.posts",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "user_id", Definition: "INT8 NOT NULL"},
+				},
+				Locality:               "REGIONAL BY ROW",
+				PrimaryKeyShardBuckets: 8,
+			},
+			{
+				Name: "public.events",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "user_id", Definition: "INT8 NOT NULL"},
+					{Name: "created_at", Definition: "DATE NOT NULL", IsPrimary: true},
+				},
+				Partitioning: &dberd.Partitioning{
+					Strategy: "RANGE",
+					Key:      "created_at",
+					Partitions: []dberd.Partition{
+						{Name: "public.events_2024_01", Bound: "FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')"},
+						{Name: "public.events_2024_02", Bound: "FOR VALUES FROM ('2024-02-01') TO ('2024-03-01')"},
+					},
+				},
+			},
+			{
+				Name: "public.legacy_users",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INTEGER NOT NULL"},
+					{Name: "login", Definition: "TEXT"},
+				},
+				ForeignServer: "legacy",
+			},
+			{
+				Name: "public.admins",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "level", Definition: "INT8 NOT NULL"},
+				},
+			},
+		},
+		References: []dberd.Reference{
+			{
+				Source:      dberd.TableColumn{Table: "public.events", Column: "user_id"},
+				Target:      dberd.TableColumn{Table: "public.users", Column: "id"},
+				Cardinality: dberd.CardinalityManyToOne,
+				Optional:    true,
+			},
+			{
+				Source:      dberd.TableColumn{Table: "public.posts", Column: "user_id"},
+				Target:      dberd.TableColumn{Table: "public.users", Column: "id"},
+				Name:        "posts_user_id_fkey",
+				OnUpdate:    "NO ACTION",
+				OnDelete:    "CASCADE",
+				Cardinality: dberd.CardinalityOneToOne,
+			},
+			{
+				Source:      dberd.TableColumn{Table: "public.users", Column: "id"},
+				Target:      dberd.TableColumn{Table: "public.posts", Column: "id"},
+				Cardinality: dberd.CardinalityManyToMany,
+				Via:         "public.post_likes",
+			},
+			{
+				Source: dberd.TableColumn{Table: "public.admins"},
+				Target: dberd.TableColumn{Table: "public.users"},
+				Kind:   dberd.ReferenceKindInheritance,
+			},
+		},
+		Routines: &dberd.Routines{
+			Sequences: []dberd.Sequence{
+				{
+					Name:    "public.events_id_seq",
+					Type:    "bigint",
+					OwnedBy: &dberd.TableColumn{Table: "public.events", Column: "id"},
+				},
+			},
+			Functions: []dberd.Function{
+				{Name: "public.audit_posts", Returns: "trigger", Language: "plpgsql"},
+				{Name: "public.audit_posts", Arguments: "post_id integer", Returns: "void", Language: "sql"},
+			},
+			Triggers: []dberd.Trigger{
+				{
+					Name:     "posts_audit",
+					Table:    "public.posts",
+					Function: "public.audit_posts()",
+					Timing:   "AFTER",
+					Events:   []string{"INSERT", "UPDATE"},
+					ForEach:  "ROW",
+				},
+			},
+		},
+		Metadata: &dberd.Metadata{
+			Source:        "postgres",
+			ServerVersion: "16.2",
+			Database:      "app",
+			ExtractedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			Version:       "v1.0.0",
+		},
+	}
+
+	ctx := context.Background()
+
+	target, err := NewTarget(WithRoutines(), WithSizeScaling())
+	require.NoError(t, err)
+
+	actual, err := target.FormatSchema(ctx, schema)
+	require.NoError(t, err)
+
+	expected := dberd.FormattedSchema{
+		Type: "d2",
+		Data: testExtendedSchema,
+	}
+	assert.Equal(t, string(expected.Data), string(actual.Data))
+}
+
 func TestRenderSchema(t *testing.T) {
 	t.Parallel()
 
@@ -130,3 +263,24 @@ func TestRenderSchema(t *testing.T) {
 
 	assert.Equal(t, testSVG, actual)
 }
+
+func TestStrokeWidth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		stats    dberd.TableStats
+		expected int
+	}{
+		{name: "empty", stats: dberd.TableStats{}, expected: 1},
+		{name: "by size", stats: dberd.TableStats{Rows: 10, SizeBytes: 2 << 30}, expected: 4},
+		{name: "by rows when size is unknown", stats: dberd.TableStats{Rows: 2_000_000}, expected: 3},
+		{name: "huge", stats: dberd.TableStats{SizeBytes: 2 << 40}, expected: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, strokeWidth(tt.stats))
+		})
+	}
+}
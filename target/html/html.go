@@ -0,0 +1,276 @@
+// Package html provides functionality for converting database schemas into a self-contained HTML explorer.
+// The explorer is a single HTML file with a searchable table list and a page per table, listing its columns,
+// indexes and references. Rendering the schema embeds a D2 diagram with pan and zoom, highlighting the
+// neighbors of the selected table.
+package html
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+	"unicode"
+
+	"github.com/holydocs/dberd"
+	"github.com/holydocs/dberd/target/d2"
+)
+
+// targetType defines the schema format type for HTML explorers.
+const targetType = dberd.TargetType("html")
+
+const (
+	// diagramPlaceholder is the diagram element of formatted explorers, replaced with the SVG on render.
+	diagramPlaceholder = `<div id="diagram"><p class="empty">No diagram, render the schema to embed it.</p></div>`
+	// schemaStart and schemaEnd enclose the JSON schema embedded in the explorer.
+	schemaStart = `<script type="application/json" id="dberd-schema">`
+	schemaEnd   = `</script>`
+)
+
+//go:embed schema.tmpl
+var templateFS embed.FS
+
+// Ensure Target implements dberd interfaces.
+var _ dberd.Target = (*Target)(nil)
+
+func init() {
+	dberd.RegisterTarget(dberd.TargetRegistration{
+		Name:        string(targetType),
+		Description: "Self-contained HTML schema explorer, renders with an embedded D2 diagram",
+		Options: []dberd.OptionSpec{
+			{Name: "size-scaling", Description: "Scale table border width by table size in the diagram", Default: "false"},
+		},
+		New: newRegisteredTarget,
+	})
+}
+
+// newRegisteredTarget creates a Target from registry options.
+func newRegisteredTarget(opts dberd.Options) (dberd.Target, error) {
+	sizeScaling, err := opts.Bool("size-scaling")
+	if err != nil {
+		return nil, err
+	}
+
+	var diagramOpts []d2.TargetOpt
+	if sizeScaling {
+		diagramOpts = append(diagramOpts, d2.WithSizeScaling())
+	}
+
+	t, err := NewTarget(WithDiagramOpts(diagramOpts...))
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Target represents an HTML explorer formatter that converts database schemas into a single HTML file.
+// Formatting produces the explorer without a diagram, rendering adds the D2 diagram to it.
+type Target struct {
+	template    *template.Template
+	diagramOpts []d2.TargetOpt
+}
+
+// TargetOpt is a function type that allows customization of a Target instance.
+type TargetOpt func(*Target)
+
+// WithDiagramOpts returns a TargetOpt that sets the options of the D2 target rendering the embedded diagram.
+func WithDiagramOpts(opts ...d2.TargetOpt) TargetOpt {
+	return func(t *Target) {
+		t.diagramOpts = opts
+	}
+}
+
+// page is the data passed to the schema template.
+type page struct {
+	Title      string
+	Tables     []tableView
+	References int
+	Diagram    template.HTML
+	Schema     dberd.Schema
+}
+
+// tableView is a table with its references, as shown on its page.
+type tableView struct {
+	dberd.Table
+	Anchor   string
+	Keywords string
+	Outbound []dberd.Reference
+	Inbound  []dberd.Reference
+}
+
+// NewTarget creates a new HTML explorer formatter instance.
+func NewTarget(opts ...TargetOpt) (*Target, error) {
+	tmpl, err := template.New("schema.tmpl").
+		Funcs(template.FuncMap{
+			"anchor": anchor,
+			"join":   strings.Join,
+		}).
+		ParseFS(templateFS, "schema.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+
+	t := &Target{
+		template: tmpl,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t, nil
+}
+
+// Capabilities returns target capabilities.
+func (t *Target) Capabilities() dberd.TargetCapabilities {
+	return dberd.TargetCapabilities{
+		Format: true,
+		Render: true,
+	}
+}
+
+// FormatSchema converts a database schema into an HTML explorer without a diagram.
+// The schema is embedded as JSON, which the explorer scripts and RenderSchema read.
+func (t *Target) FormatSchema(_ context.Context, s dberd.Schema) (dberd.FormattedSchema, error) {
+	fs := dberd.FormattedSchema{
+		Type: targetType,
+	}
+
+	var buf bytes.Buffer
+
+	err := t.template.Execute(&buf, newPage(s))
+	if err != nil {
+		return dberd.FormattedSchema{}, fmt.Errorf("executing template: %w", err)
+	}
+
+	fs.Data = buf.Bytes()
+
+	return fs, nil
+}
+
+// RenderSchema renders the D2 diagram of the schema embedded in a formatted explorer
+// and returns the explorer with the SVG diagram in place of the placeholder.
+func (t *Target) RenderSchema(ctx context.Context, s dberd.FormattedSchema) ([]byte, error) {
+	if s.Type != targetType {
+		return nil, dberd.NewUnsupportedFormatError(s.Type, targetType)
+	}
+
+	schema, err := embeddedSchema(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	diagramTarget, err := d2.NewTarget(t.diagramOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating diagram target: %w", err)
+	}
+
+	diagram, err := diagramTarget.FormatSchema(ctx, schema)
+	if err != nil {
+		return nil, fmt.Errorf("formatting diagram: %w", err)
+	}
+
+	svg, err := diagramTarget.RenderSchema(ctx, diagram)
+	if err != nil {
+		return nil, fmt.Errorf("rendering diagram: %w", err)
+	}
+
+	// The XML declaration is invalid inside an HTML document.
+	if end := bytes.Index(svg, []byte("?>")); bytes.HasPrefix(svg, []byte("<?xml")) && end >= 0 {
+		svg = svg[end+len("?>"):]
+	}
+
+	placeholder := []byte(diagramPlaceholder)
+	if !bytes.Contains(s.Data, placeholder) {
+		return nil, errors.New("diagram placeholder not found")
+	}
+
+	out := bytes.Replace(s.Data, placeholder, []byte(`<div id="diagram">`+string(svg)+`</div>`), 1)
+
+	return out, nil
+}
+
+// newPage builds the template data, collecting the inbound and outbound references of every table.
+// The template encodes the schema itself as JSON, escaping it so it can't close the script element.
+func newPage(s dberd.Schema) page {
+	p := page{
+		Title:      "Database schema",
+		Tables:     make([]tableView, len(s.Tables)),
+		References: len(s.References),
+		Diagram:    template.HTML(diagramPlaceholder),
+		Schema:     s,
+	}
+
+	if s.Metadata != nil {
+		p.Title = s.Metadata.Title()
+	}
+
+	tables := make(map[string]int, len(s.Tables))
+
+	for i, table := range s.Tables {
+		tables[table.Name] = i
+		p.Tables[i] = tableView{Table: table, Anchor: anchor(table.Name), Keywords: keywords(table)}
+	}
+
+	for _, ref := range s.References {
+		if i, ok := tables[ref.Source.Table]; ok {
+			p.Tables[i].Outbound = append(p.Tables[i].Outbound, ref)
+		}
+
+		if i, ok := tables[ref.Target.Table]; ok && ref.Target.Table != ref.Source.Table {
+			p.Tables[i].Inbound = append(p.Tables[i].Inbound, ref)
+		}
+	}
+
+	return p
+}
+
+// embeddedSchema decodes the JSON schema embedded in a formatted explorer.
+func embeddedSchema(data []byte) (dberd.Schema, error) {
+	_, rest, ok := bytes.Cut(data, []byte(schemaStart))
+	if !ok {
+		return dberd.Schema{}, errors.New("embedded schema not found")
+	}
+
+	encoded, _, ok := bytes.Cut(rest, []byte(schemaEnd))
+	if !ok {
+		return dberd.Schema{}, errors.New("embedded schema not terminated")
+	}
+
+	var schema dberd.Schema
+
+	err := json.Unmarshal(encoded, &schema)
+	if err != nil {
+		return dberd.Schema{}, fmt.Errorf("decoding embedded schema: %w", err)
+	}
+
+	return schema, nil
+}
+
+// anchor returns the element ID of a table page, e.g. "table-public.users".
+// Whitespace is replaced, as IDs can't contain it.
+func anchor(table string) string {
+	return "table-" + strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return '-'
+		}
+
+		return r
+	}, table)
+}
+
+// keywords returns the lowercase table and column names the table list search matches.
+func keywords(table dberd.Table) string {
+	words := make([]string, 0, len(table.Columns)+1)
+
+	words = append(words, table.Name)
+	for _, column := range table.Columns {
+		words = append(words, column.Name)
+	}
+
+	return strings.ToLower(strings.Join(words, " "))
+}
@@ -0,0 +1,125 @@
+package html
+
+import (
+	"context"
+	_ "embed"
+	"testing"
+	"time"
+
+	"github.com/holydocs/dberd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	//go:embed testdata/schema.html
+	testSchema []byte
+)
+
+func testDBSchema() dberd.Schema {
+	return dberd.Schema{
+		Tables: []dberd.Table{
+			{
+				Name: "public.users",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true, NotNull: true},
+					{Name: "email", Definition: "VARCHAR(255) NOT NULL", NotNull: true, Comment: "User email address, e.g. <user@example.com>"},
+				},
+				Indexes: []dberd.Index{
+					{Name: "users_pkey", Columns: []string{"id"}, IsPrimary: true},
+					{Name: "users_email_key", Columns: []string{"email"}, IsUnique: true},
+				},
+				Stats: &dberd.TableStats{Rows: 1200},
+			},
+			{
+				Name: "public.posts",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true, NotNull: true},
+					{Name: "user_id", Definition: "INT8"},
+					{Name: "parent_id", Definition: "INT8"},
+				},
+			},
+			{
+				Name: "public.tags",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true, NotNull: true},
+				},
+			},
+			{
+				Name: "public.admins",
+				Columns: []dberd.Column{
+					{Name: "level", Definition: "INT8 NOT NULL", NotNull: true},
+				},
+			},
+		},
+		References: []dberd.Reference{
+			{Source: dberd.TableColumn{Table: "public.posts", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}, OnDelete: "CASCADE"},
+			{Source: dberd.TableColumn{Table: "public.posts", Column: "parent_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}},
+			{
+				Source:      dberd.TableColumn{Table: "public.posts", Column: "id"},
+				Target:      dberd.TableColumn{Table: "public.tags", Column: "id"},
+				Via:         "public.post_tags",
+				Cardinality: dberd.CardinalityManyToMany,
+			},
+			{Source: dberd.TableColumn{Table: "public.admins"}, Target: dberd.TableColumn{Table: "public.users"}, Kind: dberd.ReferenceKindInheritance},
+		},
+		Metadata: &dberd.Metadata{
+			Source:        "postgres",
+			ServerVersion: "16.2",
+			Database:      "app",
+			ExtractedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			Version:       "v1.0.0",
+		},
+	}
+}
+
+func TestFormatSchema(t *testing.T) {
+	t.Parallel()
+
+	target, err := NewTarget()
+	require.NoError(t, err)
+
+	actual, err := target.FormatSchema(context.Background(), testDBSchema())
+	require.NoError(t, err)
+
+	expected := dberd.FormattedSchema{
+		Type: targetType,
+		Data: testSchema,
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestRenderSchema(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	target, err := NewTarget()
+	require.NoError(t, err)
+
+	schema := testDBSchema()
+
+	fs, err := target.FormatSchema(ctx, schema)
+	require.NoError(t, err)
+
+	embedded, err := embeddedSchema(fs.Data)
+	require.NoError(t, err)
+	assert.Equal(t, schema, embedded)
+
+	actual, err := target.RenderSchema(ctx, fs)
+	require.NoError(t, err)
+
+	page := string(actual)
+	assert.NotContains(t, page, diagramPlaceholder)
+	assert.NotContains(t, page, "<?xml")
+	assert.Contains(t, page, `<div id="diagram"><svg `)
+	// Table shapes are found by their base64-encoded name, see the explorer script.
+	assert.Contains(t, page, `<g class="cHVibGljLnVzZXJz"`)
+
+	_, err = target.RenderSchema(ctx, dberd.FormattedSchema{Type: "d2", Data: fs.Data})
+	require.Error(t, err)
+
+	_, err = target.RenderSchema(ctx, dberd.FormattedSchema{Type: targetType, Data: []byte("<html></html>")})
+	require.EqualError(t, err, "embedded schema not found")
+}
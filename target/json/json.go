@@ -18,6 +18,16 @@ var (
 	_ dberd.Target = (*Target)(nil)
 )
 
+func init() {
+	dberd.RegisterTarget(dberd.TargetRegistration{
+		Name:        string(targetType),
+		Description: "JSON representation of the schema",
+		New: func(_ dberd.Options) (dberd.Target, error) {
+			return NewTarget(), nil
+		},
+	})
+}
+
 // Target implements the schema formatting and rendering functionality for JSON format.
 type Target struct {
 }
@@ -0,0 +1,307 @@
+// Package markdown provides functionality for converting database schemas into a Markdown data dictionary.
+// The dictionary has a section per table with its columns and references, linking referenced tables,
+// and optionally embeds a Mermaid ERD. The layout can be replaced with a custom template.
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/holydocs/dberd"
+	"github.com/holydocs/dberd/target/mermaid"
+)
+
+// targetType defines the schema format type for Markdown data dictionaries.
+const targetType = dberd.TargetType("markdown")
+
+//go:embed schema.tmpl
+var templateFS embed.FS
+
+// Ensure Target implements dberd interfaces.
+var _ dberd.Target = (*Target)(nil)
+
+func init() {
+	dberd.RegisterTarget(dberd.TargetRegistration{
+		Name:        string(targetType),
+		Description: "Markdown data dictionary",
+		Options: []dberd.OptionSpec{
+			{Name: "mermaid", Description: "Embed a Mermaid ERD block", Default: "false"},
+			{Name: "template", Description: "Path to a template file replacing the default layout"},
+		},
+		New: newRegisteredTarget,
+	})
+}
+
+// newRegisteredTarget creates a Target from registry options.
+func newRegisteredTarget(opts dberd.Options) (dberd.Target, error) {
+	withMermaid, err := opts.Bool("mermaid")
+	if err != nil {
+		return nil, err
+	}
+
+	var targetOpts []TargetOpt
+	if withMermaid {
+		targetOpts = append(targetOpts, WithMermaid())
+	}
+
+	if path := opts["template"]; path != "" {
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading template: %w", err)
+		}
+
+		targetOpts = append(targetOpts, WithTemplate(string(text)))
+	}
+
+	t, err := NewTarget(targetOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Target represents a Markdown data dictionary formatter that converts database schemas into Markdown.
+type Target struct {
+	template     *template.Template
+	templateText string
+	mermaid      *mermaid.Target
+	withMermaid  bool
+}
+
+// TargetOpt is a function type that allows customization of a Target instance.
+type TargetOpt func(*Target)
+
+// WithMermaid returns a TargetOpt that embeds a Mermaid ERD block after the table list.
+func WithMermaid() TargetOpt {
+	return func(t *Target) {
+		t.withMermaid = true
+	}
+}
+
+// WithTemplate returns a TargetOpt that replaces the default layout with the given text/template.
+// The template is executed with a Dictionary and can use the anchor, cell and join functions.
+// Anchors are deduplicated against the headings of the default layout.
+func WithTemplate(text string) TargetOpt {
+	return func(t *Target) {
+		t.templateText = text
+	}
+}
+
+// Dictionary is the data passed to the schema template.
+type Dictionary struct {
+	Metadata *dberd.Metadata
+	Tables   []Table
+	// Mermaid is the Mermaid ERD of the schema, empty unless enabled with WithMermaid.
+	Mermaid string
+
+	// anchors are the section anchors by table name.
+	anchors map[string]string
+}
+
+// Table is a schema table with its references.
+type Table struct {
+	dberd.Table
+	Anchor string
+	// References are the references from the table, ReferencedBy are the references to it from other tables.
+	References   []dberd.Reference
+	ReferencedBy []dberd.Reference
+}
+
+// NewTarget creates a new Markdown data dictionary formatter instance.
+func NewTarget(opts ...TargetOpt) (*Target, error) {
+	t := &Target{}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	tmpl := template.New("schema.tmpl").
+		Funcs(template.FuncMap{
+			"anchor": anchor,
+			"cell":   cell,
+			"join":   strings.Join,
+		})
+
+	var err error
+
+	if t.templateText != "" {
+		tmpl, err = tmpl.Parse(t.templateText)
+	} else {
+		tmpl, err = tmpl.ParseFS(templateFS, "schema.tmpl")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+
+	t.template = tmpl
+
+	if t.withMermaid {
+		t.mermaid, err = mermaid.NewTarget()
+		if err != nil {
+			return nil, fmt.Errorf("creating mermaid target: %w", err)
+		}
+	}
+
+	return t, nil
+}
+
+// Capabilities returns target capabilities.
+func (t *Target) Capabilities() dberd.TargetCapabilities {
+	return dberd.TargetCapabilities{
+		Format: true,
+		Render: false,
+	}
+}
+
+// FormatSchema converts a database schema into a Markdown data dictionary.
+func (t *Target) FormatSchema(ctx context.Context, s dberd.Schema) (dberd.FormattedSchema, error) {
+	fs := dberd.FormattedSchema{
+		Type: targetType,
+	}
+
+	dictionary := newDictionary(s, t.mermaid != nil)
+
+	if t.mermaid != nil {
+		erd, err := t.mermaid.FormatSchema(ctx, s)
+		if err != nil {
+			return dberd.FormattedSchema{}, fmt.Errorf("formatting mermaid diagram: %w", err)
+		}
+
+		dictionary.Mermaid = strings.TrimSpace(string(erd.Data))
+	}
+
+	// Anchors depend on the schema, so the anchor function is replaced in a copy of the template.
+	tmpl, err := t.template.Clone()
+	if err != nil {
+		return dberd.FormattedSchema{}, fmt.Errorf("cloning template: %w", err)
+	}
+
+	tmpl.Funcs(template.FuncMap{"anchor": dictionary.tableAnchor})
+
+	var buf bytes.Buffer
+
+	err = tmpl.Execute(&buf, dictionary)
+	if err != nil {
+		return dberd.FormattedSchema{}, fmt.Errorf("executing template: %w", err)
+	}
+
+	fs.Data = buf.Bytes()
+
+	return fs, nil
+}
+
+// RenderSchema is unsupported for markdown target.
+func (t *Target) RenderSchema(_ context.Context, _ dberd.FormattedSchema) ([]byte, error) {
+	return nil, errors.New("unsupported")
+}
+
+// newDictionary builds the template data, collecting the references from and to every table.
+// Table anchors follow the headings of the default layout, including the diagram one if withDiagram is set.
+func newDictionary(s dberd.Schema, withDiagram bool) Dictionary {
+	d := Dictionary{
+		Metadata: s.Metadata,
+		Tables:   make([]Table, len(s.Tables)),
+		anchors:  make(map[string]string, len(s.Tables)),
+	}
+
+	headings := headingAnchors{}
+	headings.add("Data Dictionary")
+	headings.add("Tables")
+
+	if withDiagram {
+		headings.add("Diagram")
+	}
+
+	tables := make(map[string]int, len(s.Tables))
+
+	for i, table := range s.Tables {
+		tables[table.Name] = i
+		d.anchors[table.Name] = headings.add(table.Name)
+		d.Tables[i] = Table{Table: table, Anchor: d.anchors[table.Name]}
+	}
+
+	for _, ref := range s.References {
+		if i, ok := tables[ref.Source.Table]; ok {
+			d.Tables[i].References = append(d.Tables[i].References, ref)
+		}
+
+		if i, ok := tables[ref.Target.Table]; ok && ref.Target.Table != ref.Source.Table {
+			d.Tables[i].ReferencedBy = append(d.Tables[i].ReferencedBy, ref)
+		}
+	}
+
+	return d
+}
+
+// tableAnchor returns the anchor of a table section, or the anchor generated from the name
+// of tables missing from the dictionary.
+func (d Dictionary) tableAnchor(table string) string {
+	if a, ok := d.anchors[table]; ok {
+		return a
+	}
+
+	return anchor(table)
+}
+
+// headingAnchors counts anchors of the headings of a document. Like GitHub and GitLab,
+// it suffixes duplicates with "-1", "-2", etc., e.g. "publicusers-1" for a "publicusers" table
+// following "public.users".
+type headingAnchors map[string]int
+
+// add returns the anchor of the next heading.
+func (h headingAnchors) add(heading string) string {
+	base := anchor(heading)
+
+	a := base
+	for {
+		if _, ok := h[a]; !ok {
+			break
+		}
+
+		h[base]++
+		a = base + "-" + strconv.Itoa(h[base])
+	}
+
+	h[a] = 0
+
+	return a
+}
+
+// anchor returns the link fragment of a table section, generated from its heading the way GitHub and GitLab do:
+// lowercase letters, digits, hyphens and underscores are kept, spaces become hyphens, e.g. "publicusers"
+// for "public.users". Duplicates are suffixed by headingAnchors.
+func anchor(table string) string {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(table) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+
+	return b.String()
+}
+
+// cell escapes a value for a Markdown table cell.
+func cell(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	value = strings.ReplaceAll(value, "|", `\|`)
+
+	return strings.Join(strings.Fields(value), " ")
+}
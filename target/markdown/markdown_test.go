@@ -0,0 +1,142 @@
+package markdown
+
+import (
+	"context"
+	_ "embed"
+	"testing"
+	"time"
+
+	"github.com/holydocs/dberd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	//go:embed testdata/schema.md
+	testSchema []byte
+
+	//go:embed testdata/schema_mermaid.md
+	testMermaidSchema []byte
+)
+
+func testDBSchema() dberd.Schema {
+	return dberd.Schema{
+		Tables: []dberd.Table{
+			{
+				Name: "public.users",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "email", Definition: "VARCHAR(255) NOT NULL", NotNull: true, Comment: "User email | login"},
+					{Name: "role", Definition: "TEXT NOT NULL DEFAULT 'guest'::text", NotNull: true},
+					{Name: "created_at", Definition: "TIMESTAMP DEFAULT current_timestamp() ON UPDATE current_timestamp()"},
+				},
+				Stats: &dberd.TableStats{Rows: 1200},
+			},
+			{
+				Name: "public.posts",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "user_id", Definition: "INT8"},
+					{Name: "parent_id", Definition: "INT8"},
+				},
+			},
+			{
+				Name: "public.tags",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+				},
+			},
+			{
+				Name: "public.admins",
+				Columns: []dberd.Column{
+					{Name: "level", Definition: "INT8 NOT NULL"},
+				},
+			},
+		},
+		References: []dberd.Reference{
+			{Source: dberd.TableColumn{Table: "public.posts", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}, OnDelete: "CASCADE"},
+			{Source: dberd.TableColumn{Table: "public.posts", Column: "parent_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}},
+			{
+				Source:      dberd.TableColumn{Table: "public.posts", Column: "id"},
+				Target:      dberd.TableColumn{Table: "public.tags", Column: "id"},
+				Via:         "public.post_tags",
+				Cardinality: dberd.CardinalityManyToMany,
+			},
+			{Source: dberd.TableColumn{Table: "public.admins"}, Target: dberd.TableColumn{Table: "public.users"}, Kind: dberd.ReferenceKindInheritance},
+		},
+		Metadata: &dberd.Metadata{
+			Source:        "postgres",
+			ServerVersion: "16.2",
+			Database:      "app",
+			ExtractedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			Version:       "v1.0.0",
+		},
+	}
+}
+
+func TestFormatSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []TargetOpt
+		expected []byte
+	}{
+		{name: "default", expected: testSchema},
+		{name: "mermaid", opts: []TargetOpt{WithMermaid()}, expected: testMermaidSchema},
+		{
+			name:     "template",
+			opts:     []TargetOpt{WithTemplate("{{ range .Tables }}{{ .Name }} #{{ .Anchor }}: {{ range .Columns }}{{ .Type }} {{ end }}\n{{ end }}")},
+			expected: []byte("public.users #publicusers: INT8 VARCHAR(255) TEXT TIMESTAMP \npublic.posts #publicposts: INT8 INT8 INT8 \npublic.tags #publictags: INT8 \npublic.admins #publicadmins: INT8 \n"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			target, err := NewTarget(tt.opts...)
+			require.NoError(t, err)
+
+			actual, err := target.FormatSchema(context.Background(), testDBSchema())
+			require.NoError(t, err)
+
+			expected := dberd.FormattedSchema{
+				Type: targetType,
+				Data: tt.expected,
+			}
+
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestFormatSchemaDuplicateAnchors(t *testing.T) {
+	t.Parallel()
+
+	schema := dberd.Schema{
+		Tables: []dberd.Table{
+			{Name: "public.users", Columns: []dberd.Column{{Name: "id", Definition: "INT8"}}},
+			{Name: "publicusers", Columns: []dberd.Column{{Name: "id", Definition: "INT8"}}},
+			{Name: "tables", Columns: []dberd.Column{{Name: "user_id", Definition: "INT8"}}},
+		},
+		References: []dberd.Reference{
+			{Source: dberd.TableColumn{Table: "tables", Column: "user_id"}, Target: dberd.TableColumn{Table: "publicusers", Column: "id"}},
+		},
+	}
+
+	target, err := NewTarget(WithTemplate(`{{ range .Tables }}{{ .Anchor }} {{ range .References }}{{ anchor .Target.Table }}{{ end }}
+{{ end }}`))
+	require.NoError(t, err)
+
+	actual, err := target.FormatSchema(context.Background(), schema)
+	require.NoError(t, err)
+	assert.Equal(t, "publicusers \npublicusers-1 \ntables-1 publicusers-1\n", string(actual.Data))
+}
+
+func TestNewTarget(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewTarget(WithTemplate("{{ .Tables"))
+	require.ErrorContains(t, err, "parsing template: ")
+}
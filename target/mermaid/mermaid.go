@@ -20,6 +20,16 @@ var templateFS embed.FS
 // Ensure Target implements dberd interfaces.
 var _ dberd.Target = (*Target)(nil)
 
+func init() {
+	dberd.RegisterTarget(dberd.TargetRegistration{
+		Name:        string(targetType),
+		Description: "Mermaid JS entity relationship diagram",
+		New: func(_ dberd.Options) (dberd.Target, error) {
+			return NewTarget()
+		},
+	})
+}
+
 // Target represents a Mermaid JS diagram formatter that converts database schemas into Mermaid JS format.
 type Target struct {
 	template *template.Template
@@ -27,7 +37,9 @@ type Target struct {
 
 // NewTarget creates a new Mermaid JS diagram formatter instance.
 func NewTarget() (*Target, error) {
-	tmpl, err := template.ParseFS(templateFS, "schema.tmpl")
+	tmpl, err := template.New("schema.tmpl").
+		Funcs(template.FuncMap{"relationship": relationship}).
+		ParseFS(templateFS, "schema.tmpl")
 	if err != nil {
 		return nil, fmt.Errorf("parsing template: %w", err)
 	}
@@ -67,3 +79,24 @@ func (t *Target) FormatSchema(_ context.Context, s dberd.Schema) (dberd.Formatte
 func (t *Target) RenderSchema(_ context.Context, _ dberd.FormattedSchema) ([]byte, error) {
 	return nil, fmt.Errorf("unsupported")
 }
+
+// Crow's foot glyphs of the left (source) and right (target) relationship ends.
+var (
+	sourceGlyphs = map[dberd.Multiplicity]string{
+		dberd.MultiplicityZeroOrOne:  "|o",
+		dberd.MultiplicityExactlyOne: "||",
+		dberd.MultiplicityZeroOrMany: "}o",
+	}
+	targetGlyphs = map[dberd.Multiplicity]string{
+		dberd.MultiplicityZeroOrOne:  "o|",
+		dberd.MultiplicityExactlyOne: "||",
+		dberd.MultiplicityZeroOrMany: "o{",
+	}
+)
+
+// relationship returns crow's foot notation for a reference, see dberd.Reference.Multiplicities.
+func relationship(ref dberd.Reference) string {
+	source, target := ref.Multiplicities()
+
+	return sourceGlyphs[source] + "--" + targetGlyphs[target]
+}
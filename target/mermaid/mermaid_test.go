@@ -4,6 +4,7 @@ import (
 	"context"
 	_ "embed"
 	"testing"
+	"time"
 
 	"github.com/holydocs/dberd"
 	"github.com/stretchr/testify/assert"
@@ -56,11 +57,35 @@ func TestFormatSchema(t *testing.T) {
 					{Name: "created_at", Definition: "TIMESTAMP DEFAULT current_timestamp()"},
 				},
 			},
+			{
+				Name: "public.legacy_users",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INTEGER NOT NULL"},
+					{Name: "login", Definition: "TEXT"},
+				},
+				ForeignServer: "legacy",
+			},
+			{
+				Name: "public.admins",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "level", Definition: "INT8 NOT NULL"},
+				},
+			},
 		},
 		References: []dberd.Reference{
-			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "role_id"}, Target: dberd.TableColumn{Table: "public.roles", Column: "id"}},
+			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "role_id"}, Target: dberd.TableColumn{Table: "public.roles", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
 			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.posts", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}},
+			{Source: dberd.TableColumn{Table: "public.posts", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}, OnDelete: "CASCADE", Cardinality: dberd.CardinalityOneToOne, Optional: true},
+			{Source: dberd.TableColumn{Table: "public.users", Column: "id"}, Target: dberd.TableColumn{Table: "public.roles", Column: "id"}, Cardinality: dberd.CardinalityManyToMany, Via: "public.user_groups"},
+			{Source: dberd.TableColumn{Table: "public.admins"}, Target: dberd.TableColumn{Table: "public.users"}, Kind: dberd.ReferenceKindInheritance},
+		},
+		Metadata: &dberd.Metadata{
+			Source:        "postgres",
+			ServerVersion: "16.2",
+			Database:      "app",
+			ExtractedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			Version:       "v1.0.0",
 		},
 	}
 
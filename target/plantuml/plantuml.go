@@ -9,6 +9,7 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/holydocs/dberd"
@@ -23,6 +24,16 @@ var templateFS embed.FS
 // Ensure Target implements dberd interfaces.
 var _ dberd.Target = (*Target)(nil)
 
+func init() {
+	dberd.RegisterTarget(dberd.TargetRegistration{
+		Name:        string(targetType),
+		Description: "PlantUML entity relationship diagram",
+		New: func(_ dberd.Options) (dberd.Target, error) {
+			return NewTarget()
+		},
+	})
+}
+
 // Target represents a PlantUML diagram formatter that converts database schemas into PlantUML format.
 // It handles the conversion of database schemas to PlantUML ERD diagrams.
 type Target struct {
@@ -34,7 +45,12 @@ type Target struct {
 //
 // Returns an error if the template parsing fails.
 func NewTarget() (*Target, error) {
-	tmpl, err := template.ParseFS(templateFS, "schema.tmpl")
+	tmpl, err := template.New("schema.tmpl").
+		Funcs(template.FuncMap{
+			"join":         strings.Join,
+			"relationship": relationship,
+		}).
+		ParseFS(templateFS, "schema.tmpl")
 	if err != nil {
 		return nil, fmt.Errorf("parsing template: %w", err)
 	}
@@ -74,3 +90,24 @@ func (t *Target) FormatSchema(_ context.Context, s dberd.Schema) (dberd.Formatte
 func (t *Target) RenderSchema(_ context.Context, _ dberd.FormattedSchema) ([]byte, error) {
 	return nil, errors.New("unsupported")
 }
+
+// Crow's foot glyphs of the left (source) and right (target) relationship ends.
+var (
+	sourceGlyphs = map[dberd.Multiplicity]string{
+		dberd.MultiplicityZeroOrOne:  "|o",
+		dberd.MultiplicityExactlyOne: "||",
+		dberd.MultiplicityZeroOrMany: "}o",
+	}
+	targetGlyphs = map[dberd.Multiplicity]string{
+		dberd.MultiplicityZeroOrOne:  "o|",
+		dberd.MultiplicityExactlyOne: "||",
+		dberd.MultiplicityZeroOrMany: "o{",
+	}
+)
+
+// relationship returns crow's foot notation for a reference, see dberd.Reference.Multiplicities.
+func relationship(ref dberd.Reference) string {
+	source, target := ref.Multiplicities()
+
+	return sourceGlyphs[source] + "--" + targetGlyphs[target]
+}
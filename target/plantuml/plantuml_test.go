@@ -4,6 +4,7 @@ import (
 	"context"
 	_ "embed"
 	"testing"
+	"time"
 
 	"github.com/holydocs/dberd"
 	"github.com/stretchr/testify/assert"
@@ -28,6 +29,7 @@ func TestFormatSchema(t *testing.T) {
 					{Name: "email", Definition: "VARCHAR(255) NOT NULL", Comment: "User email address"},
 					{Name: "created_at", Definition: "TIMESTAMP DEFAULT current_timestamp()"},
 				},
+				Locality: "REGIONAL BY ROW",
 			},
 			{
 				Name: "public.roles",
@@ -55,6 +57,14 @@ func TestFormatSchema(t *testing.T) {
 					{Name: "content", Definition: "STRING"},
 					{Name: "created_at", Definition: "TIMESTAMP DEFAULT current_timestamp()"},
 				},
+				Partitioning: &dberd.Partitioning{
+					Strategy: "RANGE",
+					Key:      "created_at",
+					Partitions: []dberd.Partition{
+						{Name: "public.posts_2024", Bound: "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')"},
+						{Name: "public.posts_2025", Bound: "FOR VALUES FROM ('2025-01-01') TO ('2026-01-01')"},
+					},
+				},
 			},
 			{
 				Name: "public.categories",
@@ -83,16 +93,40 @@ func TestFormatSchema(t *testing.T) {
 					{Name: "created_at", Definition: "TIMESTAMP DEFAULT current_timestamp()"},
 				},
 			},
+			{
+				Name: "public.legacy_users",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INTEGER NOT NULL"},
+					{Name: "login", Definition: "TEXT"},
+				},
+				ForeignServer: "legacy",
+			},
+			{
+				Name: "public.admins",
+				Columns: []dberd.Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "level", Definition: "INT8 NOT NULL"},
+				},
+			},
 		},
 		References: []dberd.Reference{
-			{Source: dberd.TableColumn{Table: "public.categories", Column: "parent_id"}, Target: dberd.TableColumn{Table: "public.categories", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.comments", Column: "post_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}},
+			{Source: dberd.TableColumn{Table: "public.categories", Column: "parent_id"}, Target: dberd.TableColumn{Table: "public.categories", Column: "id"}, Cardinality: dberd.CardinalityManyToOne, Optional: true},
+			{Source: dberd.TableColumn{Table: "public.comments", Column: "post_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}, OnDelete: "CASCADE", OnUpdate: "NO ACTION"},
 			{Source: dberd.TableColumn{Table: "public.comments", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}},
 			{Source: dberd.TableColumn{Table: "public.post_categories", Column: "category_id"}, Target: dberd.TableColumn{Table: "public.categories", Column: "id"}},
 			{Source: dberd.TableColumn{Table: "public.post_categories", Column: "post_id"}, Target: dberd.TableColumn{Table: "public.posts", Column: "id"}},
-			{Source: dberd.TableColumn{Table: "public.posts", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}},
+			{Source: dberd.TableColumn{Table: "public.posts", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}, Cardinality: dberd.CardinalityManyToOne},
 			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "role_id"}, Target: dberd.TableColumn{Table: "public.roles", Column: "id"}},
 			{Source: dberd.TableColumn{Table: "public.user_roles", Column: "user_id"}, Target: dberd.TableColumn{Table: "public.users", Column: "id"}},
+			{Source: dberd.TableColumn{Table: "public.users", Column: "id"}, Target: dberd.TableColumn{Table: "public.roles", Column: "id"}, Cardinality: dberd.CardinalityManyToMany, Via: "public.user_groups"},
+			{Source: dberd.TableColumn{Table: "public.admins"}, Target: dberd.TableColumn{Table: "public.users"}, Kind: dberd.ReferenceKindInheritance},
+		},
+		Metadata: &dberd.Metadata{
+			Source:        "postgres",
+			ServerVersion: "16.2",
+			Database:      "app",
+			ExtractedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			Version:       "v1.0.0",
 		},
 	}
 
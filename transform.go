@@ -0,0 +1,348 @@
+package dberd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Transformer defines the interface for transforming a database schema before it is formatted,
+// e.g. filtering or renaming its parts. Implementations must not modify the given schema in place.
+type Transformer interface {
+	Transform(ctx context.Context, s Schema) (Schema, error)
+}
+
+// TransformerFunc is an adapter to allow the use of ordinary functions as transformers.
+type TransformerFunc func(ctx context.Context, s Schema) (Schema, error)
+
+// Transform calls f(ctx, s).
+func (f TransformerFunc) Transform(ctx context.Context, s Schema) (Schema, error) {
+	return f(ctx, s)
+}
+
+// Pipeline is a transformer applying its transformers in order.
+type Pipeline []Transformer
+
+// Transform applies the pipeline transformers in order, passing each the result of the previous one.
+func (p Pipeline) Transform(ctx context.Context, s Schema) (Schema, error) {
+	for i, t := range p {
+		var err error
+
+		s, err = t.Transform(ctx, s)
+		if err != nil {
+			return Schema{}, fmt.Errorf("transformer %d: %w", i, err)
+		}
+	}
+
+	return s, nil
+}
+
+// DropColumns returns a transformer removing columns whose name or table-qualified name
+// (e.g. "public.users.created_at") fully matches the given regular expression,
+// along with references and index columns involving them.
+func DropColumns(pattern string) (Transformer, error) {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("compiling column pattern: %w", err)
+	}
+
+	drop := func(table, column string) bool {
+		return re.MatchString(column) || re.MatchString(table+"."+column)
+	}
+
+	return TransformerFunc(func(_ context.Context, s Schema) (Schema, error) {
+		s.Tables = slices.Clone(s.Tables)
+
+		for i, table := range s.Tables {
+			s.Tables[i].Columns = slices.DeleteFunc(slices.Clone(table.Columns), func(c Column) bool {
+				return drop(table.Name, c.Name)
+			})
+
+			var indexes []Index
+
+			for _, index := range table.Indexes {
+				index.Columns = slices.DeleteFunc(slices.Clone(index.Columns), func(c string) bool {
+					return drop(table.Name, c)
+				})
+
+				if len(index.Columns) > 0 {
+					indexes = append(indexes, index)
+				}
+			}
+
+			s.Tables[i].Indexes = indexes
+		}
+
+		s.References = slices.DeleteFunc(slices.Clone(s.References), func(ref Reference) bool {
+			if ref.IsInheritance() {
+				return false
+			}
+
+			return drop(ref.Source.Table, ref.Source.Column) || drop(ref.Target.Table, ref.Target.Column)
+		})
+
+		return s, nil
+	}), nil
+}
+
+// StripNamespace returns a transformer removing the namespace (schema or database) prefix
+// from table names, e.g. "public.users" becomes "users". When namespaces are given,
+// only those are stripped. It fails if stripping makes table names ambiguous.
+func StripNamespace(namespaces ...string) Transformer {
+	return TransformerFunc(func(_ context.Context, s Schema) (Schema, error) {
+		rename := func(name string) string {
+			namespace, rest, found := strings.Cut(name, ".")
+			if !found || (len(namespaces) > 0 && !slices.Contains(namespaces, namespace)) {
+				return name
+			}
+
+			return rest
+		}
+
+		seen := make(map[string]string, len(s.Tables))
+
+		for _, table := range s.Tables {
+			name := rename(table.Name)
+			if other, ok := seen[name]; ok {
+				return Schema{}, fmt.Errorf("stripping namespace: %s and %s are both named %s", other, table.Name, name)
+			}
+
+			seen[name] = table.Name
+		}
+
+		return renameTables(s, rename), nil
+	})
+}
+
+// HideUnreferencedTables returns a transformer removing tables which neither reference
+// nor are referenced by other tables.
+func HideUnreferencedTables() Transformer {
+	return TransformerFunc(func(_ context.Context, s Schema) (Schema, error) {
+		referenced := make(map[string]bool)
+
+		for _, ref := range s.References {
+			referenced[ref.Source.Table] = true
+			referenced[ref.Target.Table] = true
+		}
+
+		return filterTables(s, func(name string) bool {
+			return referenced[name]
+		}), nil
+	})
+}
+
+// FilterTables returns a transformer keeping only tables whose name (e.g. "public.users")
+// fully matches the include regular expression and doesn't match the exclude one.
+// An empty pattern includes all tables or excludes none respectively.
+func FilterTables(include, exclude string) (Transformer, error) {
+	var includeRe, excludeRe *regexp.Regexp
+
+	if include != "" {
+		re, err := regexp.Compile("^(?:" + include + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("compiling include pattern: %w", err)
+		}
+
+		includeRe = re
+	}
+
+	if exclude != "" {
+		re, err := regexp.Compile("^(?:" + exclude + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("compiling exclude pattern: %w", err)
+		}
+
+		excludeRe = re
+	}
+
+	return TransformerFunc(func(_ context.Context, s Schema) (Schema, error) {
+		return filterTables(s, func(name string) bool {
+			return (includeRe == nil || includeRe.MatchString(name)) &&
+				(excludeRe == nil || !excludeRe.MatchString(name))
+		}), nil
+	}), nil
+}
+
+// CollapseJunctionTables returns a transformer replacing pure junction tables with a single
+// many-to-many reference between the tables they join. A pure junction table has a primary key
+// of exactly two columns, each referencing another table, and no other columns except date and
+// time ones, e.g. "assigned_at". Junction tables referenced by other tables are kept.
+func CollapseJunctionTables() Transformer {
+	return TransformerFunc(func(_ context.Context, s Schema) (Schema, error) {
+		referenced := make(map[string]bool)
+		outgoing := make(map[string][]Reference)
+
+		for _, ref := range s.References {
+			if ref.IsInheritance() {
+				referenced[ref.Source.Table] = true
+				referenced[ref.Target.Table] = true
+
+				continue
+			}
+
+			referenced[ref.Target.Table] = true
+			outgoing[ref.Source.Table] = append(outgoing[ref.Source.Table], ref)
+		}
+
+		junctions := make(map[string]bool)
+
+		var collapsed []Reference
+
+		for _, table := range s.Tables {
+			if referenced[table.Name] {
+				continue
+			}
+
+			ref, ok := junctionReference(table, outgoing[table.Name])
+			if !ok {
+				continue
+			}
+
+			junctions[table.Name] = true
+			collapsed = append(collapsed, ref)
+		}
+
+		if len(junctions) == 0 {
+			return s, nil
+		}
+
+		s = filterTables(s, func(name string) bool {
+			return !junctions[name]
+		})
+		s.References = append(s.References, collapsed...)
+
+		return s, nil
+	})
+}
+
+// timestampDefinition matches definitions of date and time columns, which junction tables
+// often have next to their keys.
+var timestampDefinition = regexp.MustCompile(`(?i)^(timestamp|timestamptz|datetime|datetime64|date)\b`)
+
+// junctionReference returns a many-to-many reference replacing the given table
+// if it is a pure junction table with the given outgoing references.
+func junctionReference(table Table, refs []Reference) (Reference, bool) {
+	var keys []Column
+
+	for _, column := range table.Columns {
+		switch {
+		case column.IsPrimary:
+			keys = append(keys, column)
+		case !timestampDefinition.MatchString(column.Definition):
+			return Reference{}, false
+		}
+	}
+
+	if len(keys) != 2 || len(refs) != 2 {
+		return Reference{}, false
+	}
+
+	var targets [2]TableColumn
+
+	for i, key := range keys {
+		j := slices.IndexFunc(refs, func(ref Reference) bool {
+			return ref.Source.Column == key.Name
+		})
+		if j < 0 {
+			return Reference{}, false
+		}
+
+		targets[i] = refs[j].Target
+	}
+
+	return Reference{
+		Source:      targets[0],
+		Target:      targets[1],
+		Cardinality: CardinalityManyToMany,
+		Via:         table.Name,
+	}, true
+}
+
+// renameTables returns a copy of the schema with every table name mapped by rename,
+// including partition names and table names in references, junctions, grants and routines.
+func renameTables(s Schema, rename func(string) string) Schema {
+	s.Tables = slices.Clone(s.Tables)
+	for i := range s.Tables {
+		s.Tables[i].Name = rename(s.Tables[i].Name)
+
+		if s.Tables[i].Partitioning != nil {
+			partitioning := *s.Tables[i].Partitioning
+
+			partitioning.Partitions = slices.Clone(partitioning.Partitions)
+			for j := range partitioning.Partitions {
+				partitioning.Partitions[j].Name = rename(partitioning.Partitions[j].Name)
+			}
+
+			s.Tables[i].Partitioning = &partitioning
+		}
+	}
+
+	s.References = slices.Clone(s.References)
+	for i := range s.References {
+		s.References[i].Source.Table = rename(s.References[i].Source.Table)
+		s.References[i].Target.Table = rename(s.References[i].Target.Table)
+
+		if s.References[i].Via != "" {
+			s.References[i].Via = rename(s.References[i].Via)
+		}
+	}
+
+	s.Grants = slices.Clone(s.Grants)
+	for i := range s.Grants {
+		s.Grants[i].Table = rename(s.Grants[i].Table)
+	}
+
+	if s.Routines != nil {
+		routines := *s.Routines
+
+		routines.Sequences = slices.Clone(routines.Sequences)
+		for i, sequence := range routines.Sequences {
+			if sequence.OwnedBy != nil {
+				ownedBy := *sequence.OwnedBy
+				ownedBy.Table = rename(ownedBy.Table)
+				routines.Sequences[i].OwnedBy = &ownedBy
+			}
+		}
+
+		routines.Triggers = slices.Clone(routines.Triggers)
+		for i := range routines.Triggers {
+			routines.Triggers[i].Table = rename(routines.Triggers[i].Table)
+		}
+
+		s.Routines = &routines
+	}
+
+	return s
+}
+
+// filterTables returns a copy of the schema with only the tables for which keep returns true,
+// along with references, grants, triggers and sequences of the kept tables. Sequences owned by
+// removed tables are removed as well, so targets don't draw their owning columns.
+func filterTables(s Schema, keep func(string) bool) Schema {
+	s.Tables = slices.DeleteFunc(slices.Clone(s.Tables), func(t Table) bool {
+		return !keep(t.Name)
+	})
+
+	s.References = slices.DeleteFunc(slices.Clone(s.References), func(ref Reference) bool {
+		return !keep(ref.Source.Table) || !keep(ref.Target.Table)
+	})
+
+	s.Grants = slices.DeleteFunc(slices.Clone(s.Grants), func(g Grant) bool {
+		return !keep(g.Table)
+	})
+
+	if s.Routines != nil {
+		routines := *s.Routines
+		routines.Triggers = slices.DeleteFunc(slices.Clone(routines.Triggers), func(t Trigger) bool {
+			return !keep(t.Table)
+		})
+		routines.Sequences = slices.DeleteFunc(slices.Clone(routines.Sequences), func(seq Sequence) bool {
+			return seq.OwnedBy != nil && !keep(seq.OwnedBy.Table)
+		})
+		s.Routines = &routines
+	}
+
+	return s
+}
@@ -0,0 +1,279 @@
+package dberd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func transformTestSchema() Schema {
+	return Schema{
+		Tables: []Table{
+			{
+				Name: "public.users",
+				Columns: []Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "created_at", Definition: "TIMESTAMP"},
+				},
+				Indexes: []Index{
+					{Name: "users_pkey", Columns: []string{"id"}, IsPrimary: true},
+					{Name: "users_created_at_idx", Columns: []string{"created_at"}},
+				},
+			},
+			{
+				Name: "public.posts",
+				Columns: []Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "user_id", Definition: "INT8 NOT NULL"},
+					{Name: "created_at", Definition: "TIMESTAMP"},
+				},
+			},
+			{
+				Name: "audit.events",
+				Columns: []Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+				},
+			},
+		},
+		References: []Reference{
+			{
+				Source: TableColumn{Table: "public.posts", Column: "user_id"},
+				Target: TableColumn{Table: "public.users", Column: "id"},
+			},
+		},
+		Grants: []Grant{
+			{Role: "app", Table: "audit.events", Privileges: []string{"SELECT"}},
+		},
+		Routines: &Routines{
+			Sequences: []Sequence{
+				{Name: "public.users_id_seq", OwnedBy: &TableColumn{Table: "public.users", Column: "id"}},
+			},
+		},
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	drop, err := DropColumns("created_at")
+	require.NoError(t, err)
+
+	input := transformTestSchema()
+
+	actual, err := Pipeline{
+		drop,
+		HideUnreferencedTables(),
+		StripNamespace(),
+	}.Transform(ctx, input)
+	require.NoError(t, err)
+
+	expected := Schema{
+		Tables: []Table{
+			{
+				Name: "users",
+				Columns: []Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+				},
+				Indexes: []Index{
+					{Name: "users_pkey", Columns: []string{"id"}, IsPrimary: true},
+				},
+			},
+			{
+				Name: "posts",
+				Columns: []Column{
+					{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "user_id", Definition: "INT8 NOT NULL"},
+				},
+			},
+		},
+		References: []Reference{
+			{
+				Source: TableColumn{Table: "posts", Column: "user_id"},
+				Target: TableColumn{Table: "users", Column: "id"},
+			},
+		},
+		Grants: []Grant{},
+		Routines: &Routines{
+			Sequences: []Sequence{
+				{Name: "public.users_id_seq", OwnedBy: &TableColumn{Table: "users", Column: "id"}},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, transformTestSchema(), input, "input schema must not be modified")
+
+	t.Run("stops on error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Pipeline{
+			TransformerFunc(func(_ context.Context, _ Schema) (Schema, error) {
+				return Schema{}, errors.New("boom")
+			}),
+		}.Transform(ctx, Schema{})
+		require.EqualError(t, err, "transformer 0: boom")
+	})
+}
+
+func TestDropColumns(t *testing.T) {
+	t.Parallel()
+
+	drop, err := DropColumns(`public\.posts\.(user_id|created_at)`)
+	require.NoError(t, err)
+
+	schema := transformTestSchema()
+
+	actual, err := drop.Transform(context.Background(), schema)
+	require.NoError(t, err)
+
+	assert.Len(t, actual.Tables[0].Columns, 2)
+	assert.Equal(t, []Column{{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true}}, actual.Tables[1].Columns)
+	assert.Empty(t, actual.References)
+	assert.Len(t, schema.References, 1)
+
+	_, err = DropColumns("(")
+	require.Error(t, err)
+}
+
+func TestFilterTables(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	filter, err := FilterTables(`public\..*`, `public\.users`)
+	require.NoError(t, err)
+
+	actual, err := filter.Transform(ctx, transformTestSchema())
+	require.NoError(t, err)
+
+	require.Len(t, actual.Tables, 1)
+	assert.Equal(t, "public.posts", actual.Tables[0].Name)
+	assert.Empty(t, actual.References)
+	assert.Empty(t, actual.Grants)
+	// The sequence owned by the excluded table is dropped with it.
+	assert.Empty(t, actual.Routines.Sequences)
+
+	filter, err = FilterTables("", "audit.*")
+	require.NoError(t, err)
+
+	actual, err = filter.Transform(ctx, transformTestSchema())
+	require.NoError(t, err)
+	assert.Len(t, actual.Tables, 2)
+	assert.Len(t, actual.References, 1)
+	assert.Len(t, actual.Routines.Sequences, 1)
+
+	_, err = FilterTables("(", "")
+	require.EqualError(t, err, "compiling include pattern: error parsing regexp: missing closing ): `^(?:()$`")
+}
+
+func TestStripNamespace(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	schema := transformTestSchema()
+	schema.Tables[2].Partitioning = &Partitioning{
+		Strategy:   "RANGE",
+		Key:        "id",
+		Partitions: []Partition{{Name: "audit.events_1", Bound: "FOR VALUES FROM (1) TO (100)"}},
+	}
+	schema.References = append(schema.References, Reference{
+		Source:      TableColumn{Table: "public.users", Column: "id"},
+		Target:      TableColumn{Table: "public.posts", Column: "id"},
+		Cardinality: CardinalityManyToMany,
+		Via:         "audit.user_posts",
+	})
+
+	actual, err := StripNamespace("audit").Transform(ctx, schema)
+	require.NoError(t, err)
+
+	assert.Equal(t, "public.users", actual.Tables[0].Name)
+	assert.Equal(t, "events", actual.Tables[2].Name)
+	assert.Equal(t, "events", actual.Grants[0].Table)
+	assert.Equal(t, []Partition{{Name: "events_1", Bound: "FOR VALUES FROM (1) TO (100)"}}, actual.Tables[2].Partitioning.Partitions)
+	assert.Equal(t, "user_posts", actual.References[1].Via)
+	assert.Equal(t, "audit.events_1", schema.Tables[2].Partitioning.Partitions[0].Name, "the schema must not be modified")
+
+	schema = transformTestSchema()
+	schema.Tables = append(schema.Tables, Table{Name: "audit.users"})
+
+	_, err = StripNamespace().Transform(ctx, schema)
+	require.EqualError(t, err, "stripping namespace: public.users and audit.users are both named users")
+}
+
+func TestCollapseJunctionTables(t *testing.T) {
+	t.Parallel()
+
+	schema := Schema{
+		Tables: []Table{
+			{Name: "public.users", Columns: []Column{{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true}}},
+			{Name: "public.roles", Columns: []Column{{Name: "id", Definition: "INT8 NOT NULL", IsPrimary: true}}},
+			{
+				Name: "public.user_roles",
+				Columns: []Column{
+					{Name: "user_id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "role_id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "assigned_at", Definition: "TIMESTAMP DEFAULT now()"},
+				},
+			},
+			{
+				Name: "public.memberships",
+				Columns: []Column{
+					{Name: "user_id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "role_id", Definition: "INT8 NOT NULL", IsPrimary: true},
+					{Name: "level", Definition: "INT8 NOT NULL"},
+				},
+			},
+		},
+		References: []Reference{
+			{Source: TableColumn{Table: "public.user_roles", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}},
+			{Source: TableColumn{Table: "public.user_roles", Column: "role_id"}, Target: TableColumn{Table: "public.roles", Column: "id"}},
+			{Source: TableColumn{Table: "public.memberships", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}},
+			{Source: TableColumn{Table: "public.memberships", Column: "role_id"}, Target: TableColumn{Table: "public.roles", Column: "id"}},
+		},
+		Grants: []Grant{
+			{Role: "app", Table: "public.user_roles", Privileges: []string{"SELECT"}},
+		},
+	}
+
+	actual, err := CollapseJunctionTables().Transform(context.Background(), schema)
+	require.NoError(t, err)
+
+	expected := Schema{
+		Tables: []Table{schema.Tables[0], schema.Tables[1], schema.Tables[3]},
+		References: []Reference{
+			schema.References[2],
+			schema.References[3],
+			{
+				Source:      TableColumn{Table: "public.users", Column: "id"},
+				Target:      TableColumn{Table: "public.roles", Column: "id"},
+				Cardinality: CardinalityManyToMany,
+				Via:         "public.user_roles",
+			},
+		},
+		Grants: []Grant{},
+	}
+
+	assert.Equal(t, expected, actual)
+	assert.Len(t, schema.Tables, 4)
+
+	t.Run("keeps referenced junctions", func(t *testing.T) {
+		t.Parallel()
+
+		referenced := schema
+		referenced.References = append(slices.Clone(schema.References), Reference{
+			Source: TableColumn{Table: "public.users", Column: "id"},
+			Target: TableColumn{Table: "public.user_roles", Column: "user_id"},
+		})
+
+		actual, err := CollapseJunctionTables().Transform(context.Background(), referenced)
+		require.NoError(t, err)
+		assert.Equal(t, referenced, actual)
+	})
+}
@@ -0,0 +1,209 @@
+package dberd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Schema validation errors, wrapped by ValidationError.
+var (
+	// ErrDuplicateTable is a table name used by more than one table.
+	ErrDuplicateTable = errors.New("duplicate table")
+	// ErrEmptyTable is a table without columns.
+	ErrEmptyTable = errors.New("table without columns")
+	// ErrDanglingReference is a reference, trigger or sequence owner pointing at a table or column
+	// missing from the schema.
+	ErrDanglingReference = errors.New("dangling reference")
+)
+
+// ValidationError represents a single schema integrity problem.
+// Err is one of the schema validation errors, Table is the table the problem was found in
+// and Reference is the invalid reference, set for ErrDanglingReference of references only.
+type ValidationError struct {
+	Err       error
+	Table     string
+	Reference *Reference
+	Detail    string
+}
+
+// Error implements the error interface for ValidationError.
+func (e *ValidationError) Error() string {
+	return e.Err.Error() + ": " + e.Detail
+}
+
+// Unwrap returns the schema validation error, so it can be checked with errors.Is.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors aggregates schema integrity problems.
+type ValidationErrors []*ValidationError
+
+// Error implements the error interface for ValidationErrors.
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the aggregated errors, so they can be checked with errors.Is and errors.As.
+func (errs ValidationErrors) Unwrap() []error {
+	list := make([]error, len(errs))
+	for i, err := range errs {
+		list[i] = err
+	}
+
+	return list
+}
+
+// Validate checks the schema is internally consistent: table names are unique, tables have columns
+// and references, triggers and sequence owners point at existing tables and columns. It returns ValidationErrors listing
+// every problem found, or nil if the schema is valid.
+func (s Schema) Validate() error {
+	var errs ValidationErrors
+
+	tables := make(map[string]*Table, len(s.Tables))
+
+	for i := range s.Tables {
+		table := &s.Tables[i]
+
+		if _, dup := tables[table.Name]; dup {
+			errs = append(errs, &ValidationError{Err: ErrDuplicateTable, Table: table.Name, Detail: table.Name})
+			continue
+		}
+
+		tables[table.Name] = table
+
+		if len(table.Columns) == 0 {
+			errs = append(errs, &ValidationError{Err: ErrEmptyTable, Table: table.Name, Detail: table.Name})
+		}
+	}
+
+	for i := range s.References {
+		ref := &s.References[i]
+
+		problem := referenceProblem(tables, *ref)
+		if problem == "" {
+			continue
+		}
+
+		errs = append(errs, &ValidationError{
+			Err:       ErrDanglingReference,
+			Table:     ref.Source.Table,
+			Reference: ref,
+			Detail:    ref.String() + ", " + problem,
+		})
+	}
+
+	if s.Routines != nil {
+		for _, trigger := range s.Routines.Triggers {
+			if problem := triggerProblem(tables, trigger); problem != "" {
+				errs = append(errs, &ValidationError{
+					Err:    ErrDanglingReference,
+					Table:  trigger.Table,
+					Detail: "trigger " + trigger.Name + " on " + trigger.Table + ", " + problem,
+				})
+			}
+		}
+
+		for _, seq := range s.Routines.Sequences {
+			if problem := sequenceProblem(tables, seq); problem != "" {
+				errs = append(errs, &ValidationError{
+					Err:    ErrDanglingReference,
+					Table:  seq.OwnedBy.Table,
+					Detail: "sequence " + seq.Name + " owned by " + seq.OwnedBy.Table + "." + seq.OwnedBy.Column + ", " + problem,
+				})
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// DropInvalidReferences returns a transformer removing references, triggers and owned sequences pointing at
+// tables or columns missing from the schema, which are reported by Schema.Validate as ErrDanglingReference.
+func DropInvalidReferences() Transformer {
+	return TransformerFunc(func(_ context.Context, s Schema) (Schema, error) {
+		tables := make(map[string]*Table, len(s.Tables))
+		for i := range s.Tables {
+			if _, dup := tables[s.Tables[i].Name]; !dup {
+				tables[s.Tables[i].Name] = &s.Tables[i]
+			}
+		}
+
+		s.References = slices.DeleteFunc(slices.Clone(s.References), func(ref Reference) bool {
+			return referenceProblem(tables, ref) != ""
+		})
+
+		if s.Routines != nil {
+			routines := *s.Routines
+			routines.Triggers = slices.DeleteFunc(slices.Clone(routines.Triggers), func(trigger Trigger) bool {
+				return triggerProblem(tables, trigger) != ""
+			})
+			routines.Sequences = slices.DeleteFunc(slices.Clone(routines.Sequences), func(seq Sequence) bool {
+				return sequenceProblem(tables, seq) != ""
+			})
+			s.Routines = &routines
+		}
+
+		return s, nil
+	})
+}
+
+// referenceProblem describes why the reference is invalid, or returns an empty string if it is valid.
+// Columns of inheritance references are not checked, as they are always empty.
+func referenceProblem(tables map[string]*Table, ref Reference) string {
+	for _, side := range []TableColumn{ref.Source, ref.Target} {
+		table, ok := tables[side.Table]
+		if !ok {
+			return "unknown table " + side.Table
+		}
+
+		if ref.IsInheritance() {
+			continue
+		}
+
+		if _, ok := table.Column(side.Column); !ok {
+			return "unknown column " + side.Table + "." + side.Column
+		}
+	}
+
+	return ""
+}
+
+// triggerProblem describes why the trigger is invalid, or returns an empty string if it is valid.
+func triggerProblem(tables map[string]*Table, trigger Trigger) string {
+	if _, ok := tables[trigger.Table]; !ok {
+		return "unknown table " + trigger.Table
+	}
+
+	return ""
+}
+
+// sequenceProblem describes why the sequence owner is invalid, or returns an empty string if it is valid.
+// Sequences without an owner are always valid.
+func sequenceProblem(tables map[string]*Table, seq Sequence) string {
+	if seq.OwnedBy == nil {
+		return ""
+	}
+
+	table, ok := tables[seq.OwnedBy.Table]
+	if !ok {
+		return "unknown table " + seq.OwnedBy.Table
+	}
+
+	if _, ok := table.Column(seq.OwnedBy.Column); !ok {
+		return "unknown column " + seq.OwnedBy.Table + "." + seq.OwnedBy.Column
+	}
+
+	return ""
+}
@@ -0,0 +1,105 @@
+package dberd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := Schema{
+		Tables: []Table{
+			{Name: "public.users", Columns: []Column{{Name: "id", Definition: "INT8"}}},
+			{Name: "public.admins", Columns: []Column{{Name: "id", Definition: "INT8"}}},
+			{Name: "public.posts", Columns: []Column{{Name: "id", Definition: "INT8"}, {Name: "user_id", Definition: "INT8"}}},
+		},
+		References: []Reference{
+			{Source: TableColumn{Table: "public.posts", Column: "user_id"}, Target: TableColumn{Table: "public.users", Column: "id"}},
+			{Source: TableColumn{Table: "public.admins"}, Target: TableColumn{Table: "public.users"}, Kind: ReferenceKindInheritance},
+		},
+		Routines: &Routines{
+			Sequences: []Sequence{
+				{Name: "public.users_id_seq", OwnedBy: &TableColumn{Table: "public.users", Column: "id"}},
+				{Name: "public.ticket_seq"},
+			},
+			Triggers: []Trigger{
+				{Name: "posts_audit", Table: "public.posts", Function: "public.audit()"},
+			},
+		},
+	}
+
+	require.NoError(t, valid.Validate())
+
+	t.Run("routines", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := valid
+		invalid.Routines = &Routines{
+			Sequences: []Sequence{
+				valid.Routines.Sequences[0],
+				{Name: "public.posts_id_seq", OwnedBy: &TableColumn{Table: "public.posts", Column: "uid"}},
+				{Name: "public.tags_id_seq", OwnedBy: &TableColumn{Table: "public.tags", Column: "id"}},
+			},
+			Triggers: []Trigger{
+				valid.Routines.Triggers[0],
+				{Name: "tags_audit", Table: "public.tags", Function: "public.audit()"},
+			},
+		}
+
+		err := invalid.Validate()
+		require.ErrorIs(t, err, ErrDanglingReference)
+		assert.EqualError(t, err, "dangling reference: trigger tags_audit on public.tags, unknown table public.tags; "+
+			"dangling reference: sequence public.posts_id_seq owned by public.posts.uid, unknown column public.posts.uid; "+
+			"dangling reference: sequence public.tags_id_seq owned by public.tags.id, unknown table public.tags")
+
+		actual, err := DropInvalidReferences().Transform(context.Background(), invalid)
+		require.NoError(t, err)
+		assert.Equal(t, valid.Routines.Triggers, actual.Routines.Triggers)
+		assert.Equal(t, valid.Routines.Sequences[:1], actual.Routines.Sequences)
+		require.NoError(t, actual.Validate())
+		assert.Len(t, invalid.Routines.Sequences, 3)
+	})
+
+	invalid := valid
+	invalid.Tables = append(invalid.Tables, Table{Name: "public.users"}, Table{Name: "public.tags"})
+	invalid.References = append(invalid.References,
+		Reference{Source: TableColumn{Table: "public.posts", Column: "author_id"}, Target: TableColumn{Table: "public.users", Column: "id"}},
+		Reference{Source: TableColumn{Table: "public.posts", Column: "user_id"}, Target: TableColumn{Table: "public.accounts", Column: "id"}},
+	)
+
+	err := invalid.Validate()
+	require.Error(t, err)
+
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 4)
+
+	assert.ErrorIs(t, errs[0], ErrDuplicateTable)
+	assert.ErrorIs(t, errs[1], ErrEmptyTable)
+	assert.Equal(t, "public.tags", errs[1].Table)
+	assert.ErrorIs(t, errs[2], ErrDanglingReference)
+	assert.Equal(t, &invalid.References[2], errs[2].Reference)
+	require.ErrorIs(t, err, ErrDanglingReference)
+
+	assert.EqualError(t, err, "duplicate table: public.users; "+
+		"table without columns: public.tags; "+
+		"dangling reference: public.posts.author_id -> public.users.id, unknown column public.posts.author_id; "+
+		"dangling reference: public.posts.user_id -> public.accounts.id, unknown table public.accounts")
+
+	t.Run("drops invalid references", func(t *testing.T) {
+		t.Parallel()
+
+		actual, err := DropInvalidReferences().Transform(context.Background(), invalid)
+		require.NoError(t, err)
+		assert.Equal(t, valid.References, actual.References)
+		assert.Len(t, invalid.References, 4)
+
+		err = actual.Validate()
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrDanglingReference)
+	})
+}